@@ -3,23 +3,41 @@ package kcache
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"time"
 
 	lifecycle "github.com/boz/go-lifecycle"
 	logutil "github.com/boz/go-logutil"
 	"github.com/boz/kcache/client"
+	"github.com/boz/kcache/clock"
 	"github.com/boz/kcache/filter"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type Builder interface {
 	Context(context.Context) Builder
 	Log(logutil.Log) Builder
+	Metrics(Metrics) Builder
+	Tracer(Tracer) Builder
+	Topology(*Topology) Builder
+
+	// Clock() sets the time source for list refreshes,
+	// watch retries and snapshots.
+	Clock(clock.Clock) Builder
+
+	// Index() adds an index, available to cache snapshots
+	// of the controller and everything derived from it.
+	Index(string, IndexFunc) Builder
+
+	// Journal() keeps the last size events for SubscribeFrom().
+	Journal(size int) Builder
 
 	Filter(filter.Filter) Builder
 
 	Client(client.Client) Builder
 	Lister() ListerBuilder
 	Watcher() WatcherBuilder
+	Snapshot() SnapshotBuilder
 
 	Create() (Controller, error)
 }
@@ -33,24 +51,50 @@ type WatcherBuilder interface {
 	Client(client.WatchClient) WatcherBuilder
 }
 
+// SnapshotBuilder configures cache snapshots.  When a path is given, the
+// controller starts from the snapshot stored there, if any, and resumes
+// watching from its version.  Snapshots are written every period and when
+// the controller is closed.
+type SnapshotBuilder interface {
+	Path(string) SnapshotBuilder
+
+	// Object() sets the type of the cached objects, such as &corev1.Pod{}.
+	Object(metav1.Object) SnapshotBuilder
+
+	// Period() sets how often snapshots are written.  Zero only
+	// writes a snapshot when the controller is closed.
+	Period(time.Duration) SnapshotBuilder
+}
+
 func NewBuilder() Builder {
 	return &builder{
-		filter: filter.Null(),
-		log:    logutil.Default(),
-		ctx:    context.Background(),
-		lb:     newListerBuilder(),
-		wb:     newWatcherBuilder(),
+		filter:   filter.Null(),
+		log:      logutil.Default(),
+		metrics:  NullMetrics(),
+		tracer:   NullTracer(),
+		ctx:      context.Background(),
+		lb:       newListerBuilder(),
+		wb:       newWatcherBuilder(),
+		sb:       newSnapshotBuilder(),
+		indexers: Indexers{},
 	}
 }
 
 type builder struct {
-	client client.Client
-	log    logutil.Log
-	ctx    context.Context
-	filter filter.Filter
+	client   client.Client
+	log      logutil.Log
+	metrics  Metrics
+	tracer   Tracer
+	topology *Topology
+	clock    clock.Clock
+	ctx      context.Context
+	filter   filter.Filter
+	indexers Indexers
+	journal  int
 
 	lb *listerBuilder
 	wb *watcherBuilder
+	sb *snapshotBuilder
 }
 
 func (b *builder) Context(ctx context.Context) Builder {
@@ -63,6 +107,36 @@ func (b *builder) Log(log logutil.Log) Builder {
 	return b
 }
 
+func (b *builder) Metrics(metrics Metrics) Builder {
+	b.metrics = metrics
+	return b
+}
+
+func (b *builder) Tracer(tracer Tracer) Builder {
+	b.tracer = tracer
+	return b
+}
+
+func (b *builder) Topology(topology *Topology) Builder {
+	b.topology = topology
+	return b
+}
+
+func (b *builder) Index(name string, fn IndexFunc) Builder {
+	b.indexers[name] = fn
+	return b
+}
+
+func (b *builder) Journal(size int) Builder {
+	b.journal = size
+	return b
+}
+
+func (b *builder) Clock(clock clock.Clock) Builder {
+	b.clock = clock
+	return b
+}
+
 func (b *builder) Filter(filter filter.Filter) Builder {
 	b.filter = filter
 	return b
@@ -82,6 +156,10 @@ func (b *builder) Watcher() WatcherBuilder {
 	return b.wb
 }
 
+func (b *builder) Snapshot() SnapshotBuilder {
+	return b.sb
+}
+
 func (b *builder) Create() (Controller, error) {
 	if b.log == nil {
 		return nil, fmt.Errorf("kcache builder: log required")
@@ -90,30 +168,70 @@ func (b *builder) Create() (Controller, error) {
 	log := b.log.WithComponent("controller")
 	ctx := b.ctx
 
+	snapshot, err := b.sb.create()
+	if err != nil {
+		return nil, err
+	}
+
+	var warm *warmStart
+	if snapshot != nil {
+		version, objs, err := snapshot.read()
+		if err != nil {
+			log.ErrWarn(err, "snapshot: starting cold")
+		} else {
+			warm = &warmStart{version, objs, snapshot.prototype.Name()}
+		}
+	}
+
+	env := defaultEnvironment()
+	if b.metrics != nil {
+		env.metrics = b.metrics
+	}
+	if b.tracer != nil {
+		env.tracer = b.tracer
+	}
+	if b.clock != nil {
+		env.clock = b.clock
+	}
+	for name, fn := range b.indexers {
+		env.indexers[name] = fn
+	}
+
+	var journal *journal
+	if b.journal > 0 {
+		journal = newJournal(b.journal)
+	}
+
 	lc := lifecycle.New()
 
-	cache := newCache(ctx, log, lc.ShuttingDown(), b.filter)
+	cache := newCache(ctx, log, env, "controller", lc.ShuttingDown(), b.filter)
 	readych := make(chan struct{})
 
-	subscription := newSubscription(log, lc.ShuttingDown(), readych, cache)
-	publisher := newPublisher(log, subscription)
-
 	c := &controller{
 		readych: readych,
 
-		subscription: subscription,
-		publisher:    publisher,
-
-		lister:  newLister(ctx, log, lc.ShuttingDown(), b.lb.period, b.lb.client),
-		watcher: newWatcher(ctx, log, lc.ShuttingDown(), b.wb.client),
+		lister:  newLister(ctx, log, env, lc.ShuttingDown(), b.lb.period, b.lb.client, warm != nil),
+		watcher: newWatcher(ctx, log, env, lc.ShuttingDown(), b.wb.client),
 
 		cache: cache,
 
+		snapshot: snapshot,
+		warm:     warm,
+
+		journal: journal,
+
+		env: env,
+
 		log: log,
 		lc:  lc,
 		ctx: ctx,
 	}
 
+	c.node = b.topology.add(nil, "controller", c)
+
+	c.subscription = newSubscription(log, env, c.node, lc.ShuttingDown(), readych, cache)
+	c.publisher = newJournalPublisher(log, env, "controller", c.subscription, journal)
+
 	go c.lc.WatchContext(c.ctx)
 
 	go c.run()
@@ -152,3 +270,41 @@ func (b *watcherBuilder) Client(client client.WatchClient) WatcherBuilder {
 	b.client = client
 	return b
 }
+
+type snapshotBuilder struct {
+	path   string
+	object metav1.Object
+	period time.Duration
+}
+
+func newSnapshotBuilder() *snapshotBuilder {
+	return &snapshotBuilder{period: defaultSnapshotPeriod}
+}
+
+func (b *snapshotBuilder) Path(path string) SnapshotBuilder {
+	b.path = path
+	return b
+}
+
+func (b *snapshotBuilder) Object(obj metav1.Object) SnapshotBuilder {
+	b.object = obj
+	return b
+}
+
+func (b *snapshotBuilder) Period(period time.Duration) SnapshotBuilder {
+	b.period = period
+	return b
+}
+
+func (b *snapshotBuilder) create() (*snapshotter, error) {
+	if b.path == "" {
+		return nil, nil
+	}
+
+	t := reflect.TypeOf(b.object)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("kcache builder: snapshot object must be a pointer")
+	}
+
+	return &snapshotter{path: b.path, prototype: t.Elem(), period: b.period}, nil
+}
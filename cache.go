@@ -15,13 +15,14 @@ type CacheReader interface {
 	GetObject(obj metav1.Object) (metav1.Object, error)
 	Get(ns string, name string) (metav1.Object, error)
 	List() ([]metav1.Object, error)
+	Snapshot() (Snapshot, error)
 }
 
 type cache interface {
 	CacheReader
-	sync([]metav1.Object) ([]Event, error)
+	sync(string, []metav1.Object) ([]Event, error)
 	update(Event) ([]Event, error)
-	refilter([]metav1.Object, filter.Filter) ([]Event, error)
+	refilter(string, []metav1.Object, filter.Filter) ([]Event, error)
 	Done() <-chan struct{}
 	Error() error
 }
@@ -37,6 +38,7 @@ type cacheEntry struct {
 }
 
 type syncRequest struct {
+	version  string
 	list     []metav1.Object
 	resultch chan<- []Event
 }
@@ -52,38 +54,50 @@ type updateRequest struct {
 }
 
 type refilterRequest struct {
+	version  string
 	list     []metav1.Object
 	filter   filter.Filter
 	resultch chan<- []Event
 }
 
 type _cache struct {
+	// kind of the node which owns the cache, for metrics.
+	kind string
+
 	filter     filter.Filter
 	syncch     chan syncRequest
 	updatech   chan updateRequest
 	refilterch chan refilterRequest
 
-	getch  chan getRequest
-	listch chan chan []metav1.Object
+	getch      chan getRequest
+	listch     chan chan []metav1.Object
+	snapshotch chan chan Snapshot
+
+	items cacheItems
 
-	items map[cacheKey]cacheEntry
+	// resource version of the last list or event.
+	version string
+
+	env environment
 
 	log logutil.Log
 	lc  lifecycle.Lifecycle
 	ctx context.Context
 }
 
-func newCache(ctx context.Context, log logutil.Log, stopch <-chan struct{}, filter filter.Filter) cache {
+func newCache(ctx context.Context, log logutil.Log, env environment, kind string, stopch <-chan struct{}, filter filter.Filter) cache {
 	log = log.WithComponent("cache")
 
 	c := &_cache{
+		kind:       kind,
 		filter:     filter,
 		syncch:     make(chan syncRequest),
 		updatech:   make(chan updateRequest),
 		getch:      make(chan getRequest),
 		refilterch: make(chan refilterRequest),
 		listch:     make(chan chan []metav1.Object),
-		items:      make(map[cacheKey]cacheEntry),
+		snapshotch: make(chan chan Snapshot),
+		env:        env,
 		log:        log,
 		lc:         lifecycle.New(),
 		ctx:        ctx,
@@ -96,9 +110,9 @@ func newCache(ctx context.Context, log logutil.Log, stopch <-chan struct{}, filt
 	return c
 }
 
-func (c *_cache) sync(list []metav1.Object) ([]Event, error) {
+func (c *_cache) sync(version string, list []metav1.Object) ([]Event, error) {
 	resultch := make(chan []Event, 1)
-	request := syncRequest{list, resultch}
+	request := syncRequest{version, list, resultch}
 
 	select {
 	case <-c.lc.ShuttingDown():
@@ -124,9 +138,9 @@ func (c *_cache) update(evt Event) ([]Event, error) {
 
 }
 
-func (c *_cache) refilter(list []metav1.Object, filter filter.Filter) ([]Event, error) {
+func (c *_cache) refilter(version string, list []metav1.Object, filter filter.Filter) ([]Event, error) {
 	resultch := make(chan []Event, 1)
-	request := refilterRequest{list, filter, resultch}
+	request := refilterRequest{version, list, filter, resultch}
 
 	select {
 	case <-c.lc.ShuttingDown():
@@ -157,6 +171,18 @@ func (c *_cache) List() ([]metav1.Object, error) {
 	return <-resultch, nil
 }
 
+func (c *_cache) Snapshot() (Snapshot, error) {
+	resultch := make(chan Snapshot, 1)
+
+	select {
+	case <-c.lc.ShuttingDown():
+		return nil, errors.WithStack(ErrNotRunning)
+	case c.snapshotch <- resultch:
+	}
+
+	return <-resultch, nil
+}
+
 func (c *_cache) GetObject(obj metav1.Object) (metav1.Object, error) {
 	return c.Get(obj.GetNamespace(), obj.GetName())
 }
@@ -176,37 +202,59 @@ func (c *_cache) Get(ns, name string) (metav1.Object, error) {
 func (c *_cache) run() {
 	defer c.lc.ShutdownCompleted()
 	for {
+		size := c.items.len()
+
 		select {
 		case request := <-c.syncch:
-			request.resultch <- c.doSync(request.list)
+			request.resultch <- c.doSync(request.version, request.list)
 		case request := <-c.updatech:
 			request.resultch <- c.doUpdate(request.evt)
 		case request := <-c.refilterch:
-			request.resultch <- c.doRefilter(request.list, request.filter)
+			request.resultch <- c.doRefilter(request.version, request.list, request.filter)
 		case request := <-c.listch:
 			request <- c.doList()
+		case request := <-c.snapshotch:
+			request <- newCacheSnapshot(c.version, c.items.snapshot(), c.env.indexers)
 		case request := <-c.getch:
-			if entry, ok := c.items[request.key]; ok {
+			if entry, ok := c.items.get(request.key); ok {
 				request.resultch <- entry.object
 			} else {
 				request.resultch <- nil
 			}
 		case err := <-c.lc.ShutdownRequest():
+			c.env.metrics.CacheResized(c.kind, -size)
 			c.lc.ShutdownInitiated(err)
 			return
 		}
+
+		if delta := c.items.len() - size; delta != 0 {
+			c.env.metrics.CacheResized(c.kind, delta)
+		}
 	}
 }
 
 func (c *_cache) doList() []metav1.Object {
-	result := make([]metav1.Object, 0, len(c.items))
-	for _, obj := range c.items {
-		result = append(result, obj.object)
-	}
+	result := make([]metav1.Object, 0, c.items.len())
+	c.items.each(func(_ cacheKey, entry cacheEntry) {
+		result = append(result, entry.object)
+	})
 	return result
 }
 
-func (c *_cache) doSync(list []metav1.Object) []Event {
+func (c *_cache) doSync(version string, list []metav1.Object) []Event {
+	ctx, span := c.env.tracer.Start(detached{c.ctx}, "kcache.cache.sync",
+		IntAttribute(AttributeObjects, len(list)))
+	events := c.doSyncWith(ctx, list, c.filter)
+	c.version = version
+	span.SetAttributes(IntAttribute(AttributeEvents, len(events)))
+	span.End(nil)
+	return events
+}
+
+// doSyncWith() syncs the cache with list, using fresh
+// in place of the cache's filter for objects not yet cached.
+// Events are created with ctx.
+func (c *_cache) doSyncWith(ctx context.Context, list []metav1.Object, fresh filter.Filter) []Event {
 
 	var events []Event
 	set := make(map[cacheKey]cacheEntry)
@@ -225,17 +273,22 @@ func (c *_cache) doSync(list []metav1.Object) []Event {
 			continue
 		}
 
-		current, found := c.items[key]
+		current, found := c.items.get(key)
 
-		accept := c.filter.Accept(entry.object)
+		var accept bool
+		if found {
+			accept = c.filter.Accept(entry.object)
+		} else {
+			accept = fresh.Accept(entry.object)
+		}
 
 		switch {
 		case accept && !found:
-			events = append(events, NewEvent(EventTypeCreate, entry.object))
-			c.items[key] = entry
+			events = append(events, newEvent(ctx, EventTypeCreate, entry.object))
+			c.items.put(key, entry)
 		case accept && current.version < entry.version:
-			events = append(events, NewEvent(EventTypeUpdate, entry.object))
-			c.items[key] = entry
+			events = append(events, newEvent(ctx, EventTypeUpdate, entry.object))
+			c.items.put(key, entry)
 		case current.version >= entry.version:
 			if !c.filter.Accept(current.object) {
 				continue
@@ -248,19 +301,35 @@ func (c *_cache) doSync(list []metav1.Object) []Event {
 		set[key] = entry
 	}
 
-	for k, current := range c.items {
-		if _, ok := set[k]; !ok {
-			events = append(events, NewEvent(EventTypeDelete, current.object))
-			delete(c.items, k)
+	var removed []cacheKey
+	c.items.each(func(key cacheKey, current cacheEntry) {
+		if _, ok := set[key]; !ok {
+			events = append(events, newEvent(ctx, EventTypeDelete, current.object))
+			removed = append(removed, key)
 		}
+	})
+	for _, key := range removed {
+		c.items.remove(key)
 	}
 
 	return events
 }
 
-func (c *_cache) doRefilter(list []metav1.Object, filter filter.Filter) []Event {
-	c.filter = filter
-	return c.doSync(list)
+// doRefilter() applies f to the cache.  Objects that are not
+// cached are only evaluated against the parts of f that are
+// not in the previous filter.
+func (c *_cache) doRefilter(version string, list []metav1.Object, f filter.Filter) []Event {
+	ctx, span := c.env.tracer.Start(detached{c.ctx}, "kcache.cache.refilter",
+		IntAttribute(AttributeObjects, len(list)))
+
+	added := filter.Added(c.filter, f)
+	c.filter = f
+	events := c.doSyncWith(ctx, list, added)
+	c.version = version
+
+	span.SetAttributes(IntAttribute(AttributeEvents, len(events)))
+	span.End(nil)
+	return events
 }
 
 func (c *_cache) doUpdate(evt Event) []Event {
@@ -268,24 +337,34 @@ func (c *_cache) doUpdate(evt Event) []Event {
 
 	obj := evt.Resource()
 
+	ctx, span := c.env.tracer.Start(EventContext(evt), "kcache.cache.update", eventAttributes(evt)...)
+	defer func() {
+		span.SetAttributes(IntAttribute(AttributeEvents, len(events)))
+		span.End(nil)
+	}()
+
 	version, err := strconv.Atoi(obj.GetResourceVersion())
 	if err != nil {
 		c.log.ErrWarn(err, "resource version %v", obj.GetResourceVersion())
 		return events
 	}
 
+	if current, err := strconv.Atoi(c.version); err != nil || current < version {
+		c.version = obj.GetResourceVersion()
+	}
+
 	key := cacheKey{obj.GetNamespace(), obj.GetName()}
 	entry := cacheEntry{version, obj}
 
-	current, found := c.items[key]
+	current, found := c.items.get(key)
 
 	accept := c.filter.Accept(entry.object)
 
 	switch evt.Type() {
 	case EventTypeDelete:
 		if found {
-			events = append(events, evt)
-			delete(c.items, key)
+			events = append(events, newEvent(ctx, EventTypeDelete, obj))
+			c.items.remove(key)
 		}
 	default:
 		switch {
@@ -293,16 +372,16 @@ func (c *_cache) doUpdate(evt Event) []Event {
 			// do nothing
 		case accept && !found:
 			// create
-			events = append(events, NewEvent(EventTypeCreate, obj))
-			c.items[key] = entry
+			events = append(events, newEvent(ctx, EventTypeCreate, obj))
+			c.items.put(key, entry)
 		case accept && current.version < entry.version:
 			// update
-			events = append(events, NewEvent(EventTypeUpdate, obj))
-			c.items[key] = entry
+			events = append(events, newEvent(ctx, EventTypeUpdate, obj))
+			c.items.put(key, entry)
 		case !accept && current.version < entry.version:
 			// filter-delete
-			events = append(events, NewEvent(EventTypeDelete, obj))
-			delete(c.items, key)
+			events = append(events, newEvent(ctx, EventTypeDelete, obj))
+			c.items.remove(key)
 		}
 	}
 
@@ -0,0 +1,193 @@
+package kcache
+
+import "hash/fnv"
+
+const (
+	itemBits  = 5
+	itemWidth = 1 << itemBits
+	itemMask  = itemWidth - 1
+
+	// leaves with more entries are split, unless the
+	// hash is exhausted.
+	itemLeafSize = 8
+)
+
+// cacheItems is a persistent hash trie of cache entries.
+//
+// Snapshots share the nodes of the trie with the cache.  Taking
+// a snapshot starts a new generation; the cache copies a node
+// from an earlier generation before modifying it, so a write
+// after a snapshot copies only the nodes on the path to its key.
+type cacheItems struct {
+	root *itemNode
+	size int
+
+	// nodes created in gen are owned by the cache.
+	gen uint64
+}
+
+// itemNode is either an interior node with children
+// or a leaf with entries.
+type itemNode struct {
+	gen      uint64
+	children *[itemWidth]*itemNode
+	entries  []itemEntry
+}
+
+type itemEntry struct {
+	key   cacheKey
+	hash  uint64
+	entry cacheEntry
+}
+
+func (m *cacheItems) len() int {
+	return m.size
+}
+
+// snapshot() returns a read-only view of the items which
+// is not affected by later modifications.
+func (m *cacheItems) snapshot() cacheItems {
+	view := *m
+	m.gen++
+	return view
+}
+
+func (m *cacheItems) get(key cacheKey) (cacheEntry, bool) {
+	hash := hashCacheKey(key)
+	node := m.root
+	for shift := uint(0); node != nil; shift += itemBits {
+		if node.children == nil {
+			for _, e := range node.entries {
+				if e.key == key {
+					return e.entry, true
+				}
+			}
+			break
+		}
+		node = node.children[(hash>>shift)&itemMask]
+	}
+	return cacheEntry{}, false
+}
+
+func (m *cacheItems) put(key cacheKey, entry cacheEntry) {
+	hash := hashCacheKey(key)
+
+	if m.root == nil {
+		m.root = &itemNode{gen: m.gen}
+	}
+	m.root = m.own(m.root)
+
+	node := m.root
+	shift := uint(0)
+	for ; node.children != nil; shift += itemBits {
+		idx := (hash >> shift) & itemMask
+		child := node.children[idx]
+		if child == nil {
+			child = &itemNode{gen: m.gen}
+		} else {
+			child = m.own(child)
+		}
+		node.children[idx] = child
+		node = child
+	}
+
+	for i := range node.entries {
+		if node.entries[i].key == key {
+			node.entries[i].entry = entry
+			return
+		}
+	}
+
+	node.entries = append(node.entries, itemEntry{key, hash, entry})
+	m.size++
+
+	if len(node.entries) > itemLeafSize && shift < 64 {
+		m.split(node, shift)
+	}
+}
+
+func (m *cacheItems) remove(key cacheKey) {
+	// don't copy the path to a missing key.
+	if _, ok := m.get(key); !ok {
+		return
+	}
+
+	hash := hashCacheKey(key)
+
+	m.root = m.own(m.root)
+	node := m.root
+	for shift := uint(0); node.children != nil; shift += itemBits {
+		idx := (hash >> shift) & itemMask
+		child := m.own(node.children[idx])
+		node.children[idx] = child
+		node = child
+	}
+
+	for i := range node.entries {
+		if node.entries[i].key == key {
+			node.entries = append(node.entries[:i], node.entries[i+1:]...)
+			m.size--
+			return
+		}
+	}
+}
+
+// each() calls fn for every entry, in no particular order.
+// fn must not modify the items.
+func (m *cacheItems) each(fn func(cacheKey, cacheEntry)) {
+	eachItem(m.root, fn)
+}
+
+func eachItem(node *itemNode, fn func(cacheKey, cacheEntry)) {
+	if node == nil {
+		return
+	}
+	if node.children == nil {
+		for _, e := range node.entries {
+			fn(e.key, e.entry)
+		}
+		return
+	}
+	for _, child := range node.children {
+		eachItem(child, fn)
+	}
+}
+
+// own() returns node if it belongs to the current
+// generation, and a copy of it otherwise.
+func (m *cacheItems) own(node *itemNode) *itemNode {
+	if node.gen == m.gen {
+		return node
+	}
+	owned := &itemNode{gen: m.gen}
+	if node.children != nil {
+		children := *node.children
+		owned.children = &children
+	} else {
+		owned.entries = append([]itemEntry(nil), node.entries...)
+	}
+	return owned
+}
+
+// split() turns the owned leaf node into an interior node
+// whose children are indexed by the hash bits at shift.
+func (m *cacheItems) split(node *itemNode, shift uint) {
+	children := new([itemWidth]*itemNode)
+	for _, e := range node.entries {
+		idx := (e.hash >> shift) & itemMask
+		if children[idx] == nil {
+			children[idx] = &itemNode{gen: m.gen}
+		}
+		children[idx].entries = append(children[idx].entries, e)
+	}
+	node.children = children
+	node.entries = nil
+}
+
+func hashCacheKey(key cacheKey) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key.namespace))
+	h.Write([]byte{0})
+	h.Write([]byte(key.name))
+	return h.Sum64()
+}
@@ -0,0 +1,59 @@
+package kcache
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheItems(t *testing.T) {
+	const count = 1000
+
+	key := func(i int) cacheKey {
+		return cacheKey{"ns-" + strconv.Itoa(i%7), "obj-" + strconv.Itoa(i)}
+	}
+
+	var items cacheItems
+	for i := 0; i < count; i++ {
+		items.put(key(i), cacheEntry{version: i})
+	}
+	require.Equal(t, count, items.len())
+
+	snap := items.snapshot()
+
+	// replace the even entries and remove the odd ones.
+	for i := 0; i < count; i++ {
+		if i%2 == 0 {
+			items.put(key(i), cacheEntry{version: i + count})
+		} else {
+			items.remove(key(i))
+		}
+	}
+	items.remove(cacheKey{"ns-0", "missing"})
+
+	assert.Equal(t, count/2, items.len())
+	assert.Equal(t, count, snap.len())
+
+	for i := 0; i < count; i++ {
+		entry, ok := snap.get(key(i))
+		require.True(t, ok, i)
+		assert.Equal(t, i, entry.version)
+
+		entry, ok = items.get(key(i))
+		if i%2 == 0 {
+			require.True(t, ok, i)
+			assert.Equal(t, i+count, entry.version)
+		} else {
+			assert.False(t, ok, i)
+		}
+	}
+
+	seen := make(map[cacheKey]bool)
+	items.each(func(key cacheKey, _ cacheEntry) {
+		assert.False(t, seen[key], key)
+		seen[key] = true
+	})
+	assert.Len(t, seen, count/2)
+}
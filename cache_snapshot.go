@@ -0,0 +1,122 @@
+package kcache
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Snapshot is an immutable view of a cache at a single resource version.
+// It is not affected by later updates to the cache.
+type Snapshot interface {
+	// ResourceVersion() is the version of the list or event
+	// that the cache was last updated from.
+	ResourceVersion() string
+
+	GetObject(obj metav1.Object) metav1.Object
+	Get(ns string, name string) metav1.Object
+	List() []metav1.Object
+
+	// ByIndex() returns the objects for which the named index
+	// produced value.  It returns ErrUnknownIndex if no such index
+	// was given to the builder.
+	ByIndex(index string, value string) ([]metav1.Object, error)
+}
+
+// IndexFunc returns the values under which obj is indexed.
+type IndexFunc func(obj metav1.Object) []string
+
+// Indexers are the named indexes available to cache snapshots.
+type Indexers map[string]IndexFunc
+
+// cacheSnapshot shares its items with the cache that created it;
+// the cache copies the parts of them that it later modifies.
+type cacheSnapshot struct {
+	version  string
+	items    cacheItems
+	indexers Indexers
+
+	// indexes are built on first use.
+	indexes map[string]map[string][]metav1.Object
+	mtx     sync.Mutex
+}
+
+func newCacheSnapshot(version string, items cacheItems, indexers Indexers) *cacheSnapshot {
+	return &cacheSnapshot{
+		version:  version,
+		items:    items,
+		indexers: indexers,
+		indexes:  make(map[string]map[string][]metav1.Object),
+	}
+}
+
+func (s *cacheSnapshot) ResourceVersion() string {
+	return s.version
+}
+
+func (s *cacheSnapshot) GetObject(obj metav1.Object) metav1.Object {
+	return s.Get(obj.GetNamespace(), obj.GetName())
+}
+
+func (s *cacheSnapshot) Get(ns, name string) metav1.Object {
+	if entry, ok := s.items.get(cacheKey{ns, name}); ok {
+		return entry.object
+	}
+	return nil
+}
+
+func (s *cacheSnapshot) List() []metav1.Object {
+	result := make([]metav1.Object, 0, s.items.len())
+	s.items.each(func(_ cacheKey, entry cacheEntry) {
+		result = append(result, entry.object)
+	})
+	return result
+}
+
+func (s *cacheSnapshot) ByIndex(index, value string) ([]metav1.Object, error) {
+	fn, ok := s.indexers[index]
+	if !ok {
+		return nil, errors.Wrap(ErrUnknownIndex, index)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	idx, ok := s.indexes[index]
+	if !ok {
+		idx = s.buildIndex(fn)
+		s.indexes[index] = idx
+	}
+
+	objs := idx[value]
+	result := make([]metav1.Object, len(objs))
+	copy(result, objs)
+	return result, nil
+}
+
+func (s *cacheSnapshot) buildIndex(fn IndexFunc) map[string][]metav1.Object {
+	keys := make([]cacheKey, 0, s.items.len())
+	s.items.each(func(key cacheKey, _ cacheEntry) {
+		keys = append(keys, key)
+	})
+
+	// index entries are ordered by namespace and name.
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].namespace != keys[j].namespace {
+			return keys[i].namespace < keys[j].namespace
+		}
+		return keys[i].name < keys[j].name
+	})
+
+	idx := make(map[string][]metav1.Object)
+	for _, key := range keys {
+		entry, _ := s.items.get(key)
+		obj := entry.object
+		for _, value := range fn(obj) {
+			idx[value] = append(idx[value], obj)
+		}
+	}
+	return idx
+}
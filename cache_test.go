@@ -2,6 +2,7 @@ package kcache
 
 import (
 	"context"
+	"strconv"
 	"testing"
 
 	logutil "github.com/boz/go-logutil"
@@ -34,13 +35,13 @@ func TestCache_Sync(t *testing.T) {
 	log := logutil.Default()
 	filter := filter.Null()
 
-	cache := newCache(ctx, log, stopch, filter)
+	cache := newCache(ctx, log, defaultEnvironment(), "controller", stopch, filter)
 
-	evs, err := cache.sync(initial)
+	evs, err := cache.sync("", initial)
 	assert.NoError(t, err)
 	assert.Len(t, evs, len(initial))
 
-	events, err := cache.sync(secondary)
+	events, err := cache.sync("", secondary)
 	assert.NoError(t, err)
 	require.Len(t, events, 3)
 
@@ -102,10 +103,10 @@ func TestCache_update(t *testing.T) {
 	log := logutil.Default()
 	filter := filter.Null()
 
-	cache := newCache(ctx, log, stopch, filter)
+	cache := newCache(ctx, log, defaultEnvironment(), "controller", stopch, filter)
 
 	// first sync returns zero events
-	evs, err := cache.sync(initial)
+	evs, err := cache.sync("", initial)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, evs)
 
@@ -166,10 +167,10 @@ func TestCache_refilter(t *testing.T) {
 
 	log := logutil.Default()
 
-	cache := newCache(ctx, log, stopch, filter.Null())
+	cache := newCache(ctx, log, defaultEnvironment(), "controller", stopch, filter.Null())
 
 	// first sync returns zero events
-	evts, err := cache.sync(initial)
+	evts, err := cache.sync("", initial)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, evts)
 
@@ -179,7 +180,7 @@ func TestCache_refilter(t *testing.T) {
 			obj.GetResourceVersion() < "5"
 	})
 
-	events, err := cache.refilter(initial, filter)
+	events, err := cache.refilter("", initial, filter)
 	assert.NoError(t, err)
 	require.Len(t, events, 1)
 
@@ -222,14 +223,81 @@ func TestCache_refilter(t *testing.T) {
 
 }
 
+func TestCache_refilter_delta(t *testing.T) {
+	initial := []metav1.Object{
+		testGenPod("default", "pod-1", "1"),
+		testGenPod("default", "pod-2", "2"),
+		testGenPod("default", "pod-3", "3"),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	log := logutil.Default()
+
+	f1 := &testCountFilter{name: "pod-1"}
+	f2 := &testCountFilter{name: "pod-2"}
+	f3 := &testCountFilter{name: "pod-3"}
+
+	cache := newCache(ctx, log, defaultEnvironment(), "controller", nil, filter.Or(f1, f2))
+
+	evts, err := cache.sync("", initial)
+	require.NoError(t, err)
+	require.Len(t, evts, 2)
+
+	f1.count, f2.count = 0, 0
+
+	// widening: objects outside the cache are only checked against f3.
+	evts, err = cache.refilter("", initial, filter.Or(f1, f2, f3))
+	require.NoError(t, err)
+	require.Len(t, evts, 1)
+	assert.Equal(t, EventTypeCreate, evts[0].Type())
+	assert.Equal(t, "pod-3", evts[0].Resource().GetName())
+	assert.Equal(t, 1, f3.count)
+
+	f1.count, f2.count, f3.count = 0, 0, 0
+
+	// narrowing: only cached objects are re-evaluated.
+	evts, err = cache.refilter("", initial, filter.Or(f1, f3))
+	require.NoError(t, err)
+	require.Len(t, evts, 1)
+	assert.Equal(t, EventTypeDelete, evts[0].Type())
+	assert.Equal(t, "pod-2", evts[0].Resource().GetName())
+	assert.Equal(t, 0, f2.count)
+
+	list, err := cache.List()
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+}
+
+// testCountFilter accepts objects with the given name and
+// counts how many objects it has been asked to accept.
+type testCountFilter struct {
+	name  string
+	count int
+}
+
+func (f *testCountFilter) Accept(obj metav1.Object) bool {
+	f.count++
+	return obj.GetName() == f.name
+}
+
+func (f *testCountFilter) Equals(other filter.Filter) bool {
+	return f == other
+}
+
+func (f *testCountFilter) String() string {
+	return "count(" + f.name + ")"
+}
+
 func TestCache_lifecycle_ctx(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	log := logutil.Default()
 
-	cache := newCache(ctx, log, nil, filter.Null())
+	cache := newCache(ctx, log, defaultEnvironment(), "controller", nil, filter.Null())
 
-	evts, err := cache.sync([]metav1.Object{testGenPod("a", "b", "1")})
+	evts, err := cache.sync("", []metav1.Object{testGenPod("a", "b", "1")})
 	assert.NoError(t, err)
 	assert.Len(t, evts, 1)
 
@@ -247,7 +315,7 @@ func TestCache_lifecycle_ctx(t *testing.T) {
 
 	testutil.AssertDone(t, "cache", cache)
 
-	evts, err = cache.sync([]metav1.Object{testGenPod("a", "b", "1")})
+	evts, err = cache.sync("", []metav1.Object{testGenPod("a", "b", "1")})
 	assert.Equal(t, ErrNotRunning, errors.Cause(err))
 	assert.Nil(t, evts)
 
@@ -255,7 +323,7 @@ func TestCache_lifecycle_ctx(t *testing.T) {
 	assert.Equal(t, ErrNotRunning, errors.Cause(err))
 	assert.Nil(t, evts)
 
-	evts, err = cache.refilter([]metav1.Object{testGenPod("a", "c", "3")}, filter.All())
+	evts, err = cache.refilter("", []metav1.Object{testGenPod("a", "c", "3")}, filter.All())
 	assert.Equal(t, ErrNotRunning, errors.Cause(err))
 	assert.Nil(t, evts)
 
@@ -276,12 +344,12 @@ func TestCache_lifecycle_stopch(t *testing.T) {
 
 	log := logutil.Default()
 
-	cache := newCache(ctx, log, stopch, filter.Null())
+	cache := newCache(ctx, log, defaultEnvironment(), "controller", stopch, filter.Null())
 
 	close(stopch)
 	testutil.AssertDone(t, "cache", cache)
 
-	evts, err := cache.sync([]metav1.Object{testGenPod("a", "b", "1")})
+	evts, err := cache.sync("", []metav1.Object{testGenPod("a", "b", "1")})
 	assert.Equal(t, ErrNotRunning, errors.Cause(err))
 	assert.Nil(t, evts)
 
@@ -289,7 +357,7 @@ func TestCache_lifecycle_stopch(t *testing.T) {
 	assert.Equal(t, ErrNotRunning, errors.Cause(err))
 	assert.Nil(t, evts)
 
-	evts, err = cache.refilter([]metav1.Object{testGenPod("a", "c", "3")}, filter.All())
+	evts, err = cache.refilter("", []metav1.Object{testGenPod("a", "c", "3")}, filter.All())
 	assert.Equal(t, ErrNotRunning, errors.Cause(err))
 	assert.Nil(t, evts)
 
@@ -301,3 +369,97 @@ func TestCache_lifecycle_stopch(t *testing.T) {
 	assert.Equal(t, ErrNotRunning, errors.Cause(err))
 	assert.Nil(t, obj)
 }
+
+func TestCache_Snapshot(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := defaultEnvironment()
+	env.indexers["namespace"] = func(obj metav1.Object) []string {
+		return []string{obj.GetNamespace()}
+	}
+
+	cache := newCache(ctx, logutil.Default(), env, "controller", nil, filter.Null())
+
+	_, err := cache.sync("5", []metav1.Object{
+		testGenPod("a", "pod-1", "1"),
+		testGenPod("a", "pod-2", "2"),
+		testGenPod("b", "pod-3", "3"),
+	})
+	require.NoError(t, err)
+
+	snap, err := cache.Snapshot()
+	require.NoError(t, err)
+
+	_, err = cache.update(testGenEvent(EventTypeDelete, "a", "pod-1", "6"))
+	require.NoError(t, err)
+	_, err = cache.update(testGenEvent(EventTypeCreate, "b", "pod-4", "7"))
+	require.NoError(t, err)
+
+	// unaffected by later updates.
+	assert.Equal(t, "5", snap.ResourceVersion())
+	assert.Len(t, snap.List(), 3)
+	assert.NotNil(t, snap.Get("a", "pod-1"))
+	assert.Nil(t, snap.Get("b", "pod-4"))
+
+	objs, err := snap.ByIndex("namespace", "a")
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+	assert.Equal(t, "pod-1", objs[0].GetName())
+	assert.Equal(t, "pod-2", objs[1].GetName())
+
+	_, err = snap.ByIndex("missing", "a")
+	assert.Equal(t, ErrUnknownIndex, errors.Cause(err))
+
+	current, err := cache.Snapshot()
+	require.NoError(t, err)
+	assert.Equal(t, "7", current.ResourceVersion())
+	assert.Len(t, current.List(), 3)
+	assert.Nil(t, current.Get("a", "pod-1"))
+	assert.NotNil(t, current.GetObject(testGenPod("b", "pod-4", "")))
+
+	objs, err = current.ByIndex("namespace", "b")
+	require.NoError(t, err)
+	assert.Len(t, objs, 2)
+
+	// older events do not move the version back.
+	_, err = cache.update(testGenEvent(EventTypeUpdate, "b", "pod-3", "4"))
+	require.NoError(t, err)
+	current, err = cache.Snapshot()
+	require.NoError(t, err)
+	assert.Equal(t, "7", current.ResourceVersion())
+
+	cancel()
+	testutil.AssertDone(t, "cache", cache)
+
+	_, err = cache.Snapshot()
+	assert.Equal(t, ErrNotRunning, errors.Cause(err))
+}
+
+func BenchmarkCache_Snapshot(b *testing.B) {
+	const count = 10000
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache := newCache(ctx, logutil.Default(), defaultEnvironment(), "controller", nil, filter.Null())
+
+	list := make([]metav1.Object, 0, count)
+	for i := 0; i < count; i++ {
+		list = append(list, testGenPod("ns", "pod-"+strconv.Itoa(i), "1"))
+	}
+	_, err := cache.sync("1", list)
+	require.NoError(b, err)
+
+	b.ResetTimer()
+
+	// each update follows a snapshot.
+	for i := 0; i < b.N; i++ {
+		_, err := cache.Snapshot()
+		require.NoError(b, err)
+
+		vsn := strconv.Itoa(i + 2)
+		_, err = cache.update(testGenEvent(EventTypeUpdate, "ns", "pod-"+strconv.Itoa(i%count), vsn))
+		require.NoError(b, err)
+	}
+}
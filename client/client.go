@@ -56,12 +56,40 @@ type restRequester interface {
 	Get() *rest.Request
 }
 
+// Scoped is implemented by clients of a single resource.
+type Scoped interface {
+	// Resource() returns the name of the resource, such as "pods".
+	Resource() string
+
+	// Namespace() returns the namespace the client is limited to,
+	// or "" for all namespaces.
+	Namespace() string
+}
+
 func ForResource(
 	c restRequester, res string, ns string) Client {
-	return NewClient(
-		makeResourceListFn(c, res, ns),
-		makeResourceWatchFn(c, res, ns),
-	)
+	return &scopedClient{
+		Client: NewClient(
+			makeResourceListFn(c, res, ns),
+			makeResourceWatchFn(c, res, ns),
+		),
+		resource:  res,
+		namespace: ns,
+	}
+}
+
+type scopedClient struct {
+	Client
+	resource  string
+	namespace string
+}
+
+func (c *scopedClient) Resource() string {
+	return c.resource
+}
+
+func (c *scopedClient) Namespace() string {
+	return c.namespace
 }
 
 func makeResourceListFn(
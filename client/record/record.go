@@ -0,0 +1,302 @@
+// Package record records the list and watch traffic of a client.Client
+// and replays it.
+//
+//	f, err := os.Create("pods.jsonl")
+//	recorder := record.NewRecorder(client.ForResource(cs.CoreV1().RESTClient(), "pods", ""), f)
+//
+//	controller, err := kcache.NewBuilder().
+//	  Client(recorder).
+//	  Create()
+//
+// A recording is replayed by giving a Replayer to the builder in place
+// of the client:
+//
+//	replayer, err := record.NewReplayer(f, record.Immediate)
+//
+//	controller, err := kcache.NewBuilder().
+//	  Client(replayer).
+//	  Create()
+//
+// Recordings are written as one JSON entry per line.  Objects are
+// encoded with the types of the client-go scheme.
+package record
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/boz/kcache/client"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	entryList  = "list"
+	entryWatch = "watch"
+	entryEvent = "event"
+	entryClose = "close"
+)
+
+// entry is a single line of a recording.
+type entry struct {
+	Kind string `json:"kind"`
+
+	// Offset is the time since the recording began.  For list and
+	// watch entries, it is the time of the call.
+	Offset time.Duration `json:"offset"`
+
+	// Duration is the time taken by a list or watch call.
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// Stream identifies the watch of watch, event and close entries.
+	Stream int `json:"stream,omitempty"`
+
+	Options   *metav1.ListOptions `json:"options,omitempty"`
+	EventType watch.EventType     `json:"type,omitempty"`
+	Object    *object             `json:"object,omitempty"`
+	Error     string              `json:"error,omitempty"`
+
+	// Status holds the status of API errors.
+	Status *metav1.Status `json:"status,omitempty"`
+}
+
+type object struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	Raw        json.RawMessage `json:"raw"`
+}
+
+func encodeObject(obj runtime.Object) (*object, error) {
+	if obj == nil {
+		return nil, nil
+	}
+
+	gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, "record: object kind")
+	}
+
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, "record: encode")
+	}
+
+	apiVersion, kind := gvks[0].ToAPIVersionAndKind()
+	return &object{apiVersion, kind, raw}, nil
+}
+
+func decodeObject(o *object) (runtime.Object, error) {
+	if o == nil {
+		return nil, nil
+	}
+
+	obj, err := scheme.Scheme.New(schema.FromAPIVersionAndKind(o.APIVersion, o.Kind))
+	if err != nil {
+		return nil, errors.Wrap(err, "record: object kind")
+	}
+
+	if err := json.Unmarshal(o.Raw, obj); err != nil {
+		return nil, errors.Wrap(err, "record: decode")
+	}
+	return obj, nil
+}
+
+// setError() records err in e, keeping the status of API errors
+// so that they can be told apart when replayed.
+func (e *entry) setError(err error) {
+	if err == nil {
+		return
+	}
+	e.Error = err.Error()
+	if status, ok := err.(apierrors.APIStatus); ok {
+		s := status.Status()
+		e.Status = &s
+	}
+}
+
+func (e *entry) err() error {
+	switch {
+	case e.Status != nil:
+		return &apierrors.StatusError{ErrStatus: *e.Status}
+	case e.Error != "":
+		return errors.New(e.Error)
+	default:
+		return nil
+	}
+}
+
+// Recorder is a client.Client which records the traffic of
+// the client it wraps.
+type Recorder struct {
+	client client.Client
+	start  time.Time
+
+	enc     *json.Encoder
+	streams int
+	err     error
+	mtx     sync.Mutex
+}
+
+// NewRecorder() returns a Recorder which writes the traffic of c to w.
+func NewRecorder(c client.Client, w io.Writer) *Recorder {
+	return &Recorder{
+		client: c,
+		start:  time.Now(),
+		enc:    json.NewEncoder(w),
+	}
+}
+
+// Err() returns the first error encountered while recording.
+func (r *Recorder) Err() error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.err
+}
+
+func (r *Recorder) List(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+	start := time.Now()
+	list, err := r.client.List(ctx, opts)
+
+	e := entry{
+		Kind:     entryList,
+		Offset:   start.Sub(r.start),
+		Duration: time.Since(start),
+		Options:  &opts,
+	}
+	e.setError(err)
+	if err == nil {
+		e.Object = r.encodeObject(list)
+	}
+
+	r.write(e)
+	return list, err
+}
+
+func (r *Recorder) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	start := time.Now()
+	w, err := r.client.Watch(ctx, opts)
+
+	r.mtx.Lock()
+	r.streams++
+	stream := r.streams
+	r.mtx.Unlock()
+
+	e := entry{
+		Kind:     entryWatch,
+		Offset:   start.Sub(r.start),
+		Duration: time.Since(start),
+		Stream:   stream,
+		Options:  &opts,
+	}
+	e.setError(err)
+	r.write(e)
+
+	if err != nil {
+		return w, err
+	}
+	return newRecordWatch(r, stream, w), nil
+}
+
+func (r *Recorder) encodeObject(obj runtime.Object) *object {
+	o, err := encodeObject(obj)
+	if err != nil {
+		r.fail(err)
+	}
+	return o
+}
+
+func (r *Recorder) write(e entry) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.err != nil {
+		return
+	}
+	if err := r.enc.Encode(e); err != nil {
+		r.err = errors.Wrap(err, "record: write")
+	}
+}
+
+func (r *Recorder) fail(err error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// recordWatch forwards the events of a watch, recording each of them.
+type recordWatch struct {
+	recorder *Recorder
+	stream   int
+	parent   watch.Interface
+
+	resultch chan watch.Event
+	stopch   chan struct{}
+	once     sync.Once
+}
+
+func newRecordWatch(r *Recorder, stream int, parent watch.Interface) *recordWatch {
+	w := &recordWatch{
+		recorder: r,
+		stream:   stream,
+		parent:   parent,
+		resultch: make(chan watch.Event),
+		stopch:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *recordWatch) ResultChan() <-chan watch.Event {
+	return w.resultch
+}
+
+func (w *recordWatch) Stop() {
+	w.once.Do(func() {
+		close(w.stopch)
+		w.parent.Stop()
+	})
+}
+
+func (w *recordWatch) run() {
+	defer close(w.resultch)
+
+	for {
+		select {
+		case <-w.stopch:
+			return
+		case evt, ok := <-w.parent.ResultChan():
+			if !ok {
+				w.recorder.write(entry{
+					Kind:   entryClose,
+					Offset: time.Since(w.recorder.start),
+					Stream: w.stream,
+				})
+				return
+			}
+
+			e := entry{
+				Kind:      entryEvent,
+				Offset:    time.Since(w.recorder.start),
+				Stream:    w.stream,
+				EventType: evt.Type,
+			}
+			e.Object = w.recorder.encodeObject(evt.Object)
+			w.recorder.write(e)
+
+			select {
+			case w.resultch <- evt:
+			case <-w.stopch:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package record_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/boz/kcache"
+	"github.com/boz/kcache/client/mocks"
+	"github.com/boz/kcache/client/record"
+	"github.com/boz/kcache/testutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventch := make(chan watch.Event, 10)
+
+	mwatch := &mocks.WatchInterface{}
+	mwatch.On("ResultChan").Return(eventch)
+	mwatch.On("Stop").Return()
+
+	expired := apierrors.NewResourceExpired("too old")
+
+	client := &mocks.Client{}
+	client.On("List", mock.Anything, mock.AnythingOfType("v1.ListOptions")).
+		After(50*time.Millisecond).
+		Return(testGenList("5", testGenPod("a", "1")), nil).Once()
+	client.On("Watch", mock.Anything, mock.AnythingOfType("v1.ListOptions")).Return(mwatch, nil).Once()
+	client.On("Watch", mock.Anything, mock.AnythingOfType("v1.ListOptions")).Return(mwatch, expired).Once()
+
+	buf := &bytes.Buffer{}
+	recorder := record.NewRecorder(client, buf)
+
+	_, err := recorder.List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+
+	w, err := recorder.Watch(ctx, metav1.ListOptions{ResourceVersion: "5"})
+	require.NoError(t, err)
+
+	eventch <- watch.Event{Type: watch.Added, Object: testGenPod("b", "6")}
+	eventch <- watch.Event{Type: watch.Error, Object: &metav1.Status{Code: http.StatusGone}}
+	close(eventch)
+
+	testReadWatch(t, w, 2)
+	testWatchClosed(t, w)
+
+	_, err = recorder.Watch(ctx, metav1.ListOptions{ResourceVersion: "6"})
+	require.Error(t, err)
+
+	require.NoError(t, recorder.Err())
+
+	for _, pace := range []record.Pace{record.Immediate, record.Realtime} {
+		replayer, err := record.NewReplayer(bytes.NewReader(buf.Bytes()), pace)
+		require.NoError(t, err)
+
+		start := time.Now()
+		obj, err := replayer.List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		if pace == record.Realtime {
+			assert.True(t, time.Since(start) >= 50*time.Millisecond)
+		} else {
+			assert.True(t, time.Since(start) < 50*time.Millisecond)
+		}
+
+		require.IsType(t, &corev1.PodList{}, obj)
+		list := obj.(*corev1.PodList)
+		assert.Equal(t, "5", list.ResourceVersion)
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, "a", list.Items[0].Name)
+
+		w, err := replayer.Watch(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+
+		events := testReadWatch(t, w, 2)
+		assert.Equal(t, watch.Added, events[0].Type)
+		require.IsType(t, &corev1.Pod{}, events[0].Object)
+		assert.Equal(t, "b", events[0].Object.(*corev1.Pod).Name)
+		assert.Equal(t, watch.Error, events[1].Type)
+		require.IsType(t, &metav1.Status{}, events[1].Object)
+		assert.Equal(t, int32(http.StatusGone), events[1].Object.(*metav1.Status).Code)
+
+		// the recorded watch was closed by the server.
+		testWatchClosed(t, w)
+
+		_, err = replayer.Watch(ctx, metav1.ListOptions{})
+		assert.True(t, apierrors.IsResourceExpired(err))
+
+		testutil.AssertDone(t, "replayer", replayer)
+
+		_, err = replayer.List(ctx, metav1.ListOptions{})
+		assert.Equal(t, record.ErrExhausted, errors.Cause(err))
+	}
+}
+
+func TestReplayer_controller(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventch := make(chan watch.Event, 10)
+
+	mwatch := &mocks.WatchInterface{}
+	mwatch.On("ResultChan").Return(eventch)
+	mwatch.On("Stop").Return()
+
+	client := &mocks.Client{}
+	client.On("List", mock.Anything, mock.AnythingOfType("v1.ListOptions")).
+		Return(testGenList("5", testGenPod("a", "1"), testGenPod("b", "2")), nil)
+	client.On("Watch", mock.Anything, mock.AnythingOfType("v1.ListOptions")).Return(mwatch, nil)
+
+	buf := &testBuffer{}
+	recorder := record.NewRecorder(client, buf)
+
+	controller, err := kcache.NewBuilder().
+		Context(ctx).
+		Client(recorder).
+		Create()
+	require.NoError(t, err)
+
+	testutil.AssertReady(t, "controller", controller)
+
+	eventch <- watch.Event{Type: watch.Modified, Object: testGenPod("a", "6")}
+	eventch <- watch.Event{Type: watch.Deleted, Object: testGenPod("b", "7")}
+
+	assert.Eventually(t, func() bool {
+		return bytes.Count(buf.bytes(), []byte("\n")) == 4
+	}, time.Second, 10*time.Millisecond)
+
+	controller.Close()
+	testutil.AssertDone(t, "controller", controller)
+	require.NoError(t, recorder.Err())
+
+	replayer, err := record.NewReplayer(bytes.NewReader(buf.bytes()), record.Immediate)
+	require.NoError(t, err)
+
+	controller, err = kcache.NewBuilder().
+		Context(ctx).
+		Client(replayer).
+		Create()
+	require.NoError(t, err)
+
+	testutil.AssertReady(t, "replay", controller)
+	testutil.AssertDone(t, "replayer", replayer)
+
+	assert.Eventually(t, func() bool {
+		objs, err := controller.Cache().List()
+		return err == nil && len(objs) == 1 && objs[0].GetResourceVersion() == "6"
+	}, time.Second, 10*time.Millisecond)
+
+	controller.Close()
+	testutil.AssertDone(t, "replay", controller)
+}
+
+// testBuffer is written by the recorder while being read by the test.
+type testBuffer struct {
+	buf bytes.Buffer
+	mtx sync.Mutex
+}
+
+func (b *testBuffer) Write(p []byte) (int, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *testBuffer) bytes() []byte {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func testReadWatch(t *testing.T, w watch.Interface, count int) []watch.Event {
+	var events []watch.Event
+	for len(events) < count {
+		select {
+		case evt, ok := <-w.ResultChan():
+			require.True(t, ok, "watch closed")
+			events = append(events, evt)
+		case <-time.After(time.Second):
+			require.Fail(t, "missing events", "%v", events)
+		}
+	}
+	return events
+}
+
+func testWatchClosed(t *testing.T, w watch.Interface) {
+	select {
+	case _, ok := <-w.ResultChan():
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		require.Fail(t, "watch not closed")
+	}
+}
+
+func testGenPod(name, vsn string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "ns",
+			Name:            name,
+			ResourceVersion: vsn,
+		},
+	}
+}
+
+func testGenList(vsn string, pods ...*corev1.Pod) *corev1.PodList {
+	list := &corev1.PodList{ListMeta: metav1.ListMeta{ResourceVersion: vsn}}
+	for _, pod := range pods {
+		list.Items = append(list.Items, *pod)
+	}
+	return list
+}
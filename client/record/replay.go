@@ -0,0 +1,259 @@
+package record
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ErrExhausted is returned by a Replayer once every recorded
+// call of the same kind has been replayed.
+var ErrExhausted = errors.New("record: recording exhausted")
+
+// Pace sets the speed of a replay.
+type Pace int
+
+const (
+	// Realtime replays traffic with its recorded timing.
+	Realtime Pace = iota
+
+	// Immediate replays traffic as fast as possible.
+	Immediate
+)
+
+type replayList struct {
+	duration time.Duration
+	list     runtime.Object
+	err      error
+}
+
+type replayEvent struct {
+	// at is the time since the watch was opened.
+	at  time.Duration
+	evt watch.Event
+}
+
+type replayStream struct {
+	duration time.Duration
+	err      error
+	events   []replayEvent
+
+	// closed is set if the recorded watch was closed by the server.
+	closed bool
+}
+
+// Replayer is a client.Client which replays a recording.  Lists and
+// watches are returned in the order they were recorded, regardless of
+// the options they are called with.
+type Replayer struct {
+	pace Pace
+
+	lists   []replayList
+	streams []*replayStream
+
+	// lists and streams which have not been fully replayed.
+	pending int
+	donech  chan struct{}
+
+	mtx sync.Mutex
+}
+
+// NewReplayer() reads the recording from r.
+func NewReplayer(r io.Reader, pace Pace) (*Replayer, error) {
+	replayer := &Replayer{
+		pace:   pace,
+		donech: make(chan struct{}),
+	}
+
+	streams := make(map[int]*replayStream)
+	opened := make(map[int]time.Duration)
+
+	dec := json.NewDecoder(r)
+	for {
+		var e entry
+		if err := dec.Decode(&e); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrap(err, "record: read")
+		}
+
+		switch e.Kind {
+		case entryList:
+			list, err := decodeObject(e.Object)
+			if err != nil {
+				return nil, err
+			}
+			replayer.lists = append(replayer.lists, replayList{e.Duration, list, e.err()})
+
+		case entryWatch:
+			stream := &replayStream{duration: e.Duration, err: e.err()}
+			streams[e.Stream] = stream
+			opened[e.Stream] = e.Offset + e.Duration
+			replayer.streams = append(replayer.streams, stream)
+
+		case entryEvent:
+			stream, ok := streams[e.Stream]
+			if !ok {
+				return nil, errors.Errorf("record: event for unknown watch %v", e.Stream)
+			}
+			obj, err := decodeObject(e.Object)
+			if err != nil {
+				return nil, err
+			}
+			stream.events = append(stream.events,
+				replayEvent{e.Offset - opened[e.Stream], watch.Event{Type: e.EventType, Object: obj}})
+
+		case entryClose:
+			stream, ok := streams[e.Stream]
+			if !ok {
+				return nil, errors.Errorf("record: close for unknown watch %v", e.Stream)
+			}
+			stream.closed = true
+
+		default:
+			return nil, errors.Errorf("record: unknown entry kind %q", e.Kind)
+		}
+	}
+
+	replayer.pending = len(replayer.lists) + len(replayer.streams)
+	if replayer.pending == 0 {
+		close(replayer.donech)
+	}
+
+	return replayer, nil
+}
+
+// Done() is closed once every recorded list has been returned
+// and every recorded watch has delivered all of its events.
+func (r *Replayer) Done() <-chan struct{} {
+	return r.donech
+}
+
+func (r *Replayer) List(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+	r.mtx.Lock()
+	if len(r.lists) == 0 {
+		r.mtx.Unlock()
+		return nil, errors.WithStack(ErrExhausted)
+	}
+	list := r.lists[0]
+	r.lists = r.lists[1:]
+	r.mtx.Unlock()
+
+	defer r.replayed()
+
+	if !r.wait(list.duration, ctx.Done()) {
+		return nil, ctx.Err()
+	}
+	return list.list, list.err
+}
+
+func (r *Replayer) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	r.mtx.Lock()
+	if len(r.streams) == 0 {
+		r.mtx.Unlock()
+		return nil, errors.WithStack(ErrExhausted)
+	}
+	stream := r.streams[0]
+	r.streams = r.streams[1:]
+	r.mtx.Unlock()
+
+	if !r.wait(stream.duration, ctx.Done()) {
+		r.replayed()
+		return nil, ctx.Err()
+	}
+
+	if stream.err != nil {
+		r.replayed()
+		return nil, stream.err
+	}
+
+	return newReplayWatch(r, stream), nil
+}
+
+// wait() waits for d to pass when replaying in real time.  It
+// returns false if stopch is closed first.
+func (r *Replayer) wait(d time.Duration, stopch <-chan struct{}) bool {
+	if r.pace == Immediate || d <= 0 {
+		return true
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-stopch:
+		return false
+	}
+}
+
+func (r *Replayer) replayed() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.pending--
+	if r.pending == 0 {
+		close(r.donech)
+	}
+}
+
+type replayWatch struct {
+	replayer *Replayer
+	stream   *replayStream
+
+	resultch chan watch.Event
+	stopch   chan struct{}
+	once     sync.Once
+}
+
+func newReplayWatch(r *Replayer, stream *replayStream) *replayWatch {
+	w := &replayWatch{
+		replayer: r,
+		stream:   stream,
+		resultch: make(chan watch.Event),
+		stopch:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *replayWatch) ResultChan() <-chan watch.Event {
+	return w.resultch
+}
+
+func (w *replayWatch) Stop() {
+	w.once.Do(func() { close(w.stopch) })
+}
+
+func (w *replayWatch) run() {
+	defer close(w.resultch)
+
+	start := time.Now()
+
+	for _, evt := range w.stream.events {
+		if !w.replayer.wait(evt.at-time.Since(start), w.stopch) {
+			w.replayer.replayed()
+			return
+		}
+
+		select {
+		case w.resultch <- evt.evt:
+		case <-w.stopch:
+			w.replayer.replayed()
+			return
+		}
+	}
+
+	w.replayer.replayed()
+
+	if !w.stream.closed {
+		<-w.stopch
+	}
+}
@@ -0,0 +1,100 @@
+// Package clock provides the time source of kcache controllers.
+//
+// Controllers use the real clock unless given another with
+// Builder.Clock().  A Fake clock only moves when advanced, which
+// lets tests drive list refreshes and watch retries deterministically.
+package clock
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Clock creates timers and supplies the random jitter
+// applied to periodic work.
+type Clock interface {
+	Now() time.Time
+	Since(time.Time) time.Duration
+
+	NewTimer(time.Duration) Timer
+	NewTicker(time.Duration) Ticker
+
+	// AfterFunc() calls fn in its own goroutine after the duration.
+	AfterFunc(time.Duration, func()) Timer
+
+	// Float64() returns a random number in [0.0,1.0).
+	Float64() float64
+}
+
+type Timer interface {
+	// C() returns the channel the time is sent on.  It is
+	// nil for timers created with AfterFunc().
+	C() <-chan time.Time
+
+	Stop() bool
+	Reset(time.Duration) bool
+}
+
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real() returns a Clock backed by the time and math/rand packages.
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+func (realClock) AfterFunc(d time.Duration, fn func()) Timer {
+	return realTimer{time.AfterFunc(d, fn)}
+}
+
+func (realClock) Float64() float64 {
+	return rand.Float64()
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+func (t realTimer) Reset(d time.Duration) bool {
+	return t.timer.Reset(d)
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t realTicker) Stop() {
+	t.ticker.Stop()
+}
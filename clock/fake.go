@@ -0,0 +1,177 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a Clock whose time only moves when advanced.  Its
+// random numbers are fixed, so that jitter is predictable.
+type Fake struct {
+	now    time.Time
+	random float64
+
+	// timers and tickers which have not yet fired or been stopped.
+	pending map[*fakeTimer]struct{}
+
+	mtx  sync.Mutex
+	cond *sync.Cond
+}
+
+// NewFake() returns a Fake set to now, whose random numbers are 0.5.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{
+		now:     now,
+		random:  0.5,
+		pending: make(map[*fakeTimer]struct{}),
+	}
+	f.cond = sync.NewCond(&f.mtx)
+	return f
+}
+
+// Advance() moves the clock forward by d, firing every timer
+// and ticker which becomes due, in order.
+func (f *Fake) Advance(d time.Duration) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	end := f.now.Add(d)
+
+	for {
+		var next *fakeTimer
+		for t := range f.pending {
+			if !t.deadline.After(end) && (next == nil || t.deadline.Before(next.deadline)) {
+				next = t
+			}
+		}
+		if next == nil {
+			break
+		}
+
+		f.now = next.deadline
+		next.fire()
+
+		if next.period > 0 {
+			next.deadline = next.deadline.Add(next.period)
+		} else {
+			delete(f.pending, next)
+		}
+	}
+
+	f.now = end
+}
+
+// SetRandom() sets the number returned by Float64().
+func (f *Fake) SetRandom(v float64) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.random = v
+}
+
+// BlockUntil() waits until at least n timers and
+// tickers are waiting to fire.
+func (f *Fake) BlockUntil(n int) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for len(f.pending) < n {
+		f.cond.Wait()
+	}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.now
+}
+
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	return f.start(&fakeTimer{clock: f, ch: make(chan time.Time, 1)}, d)
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	return fakeTicker{f.start(&fakeTimer{clock: f, ch: make(chan time.Time, 1), period: d}, d)}
+}
+
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	return f.start(&fakeTimer{clock: f, fn: fn}, d)
+}
+
+func (f *Fake) Float64() float64 {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.random
+}
+
+func (f *Fake) start(t *fakeTimer, d time.Duration) *fakeTimer {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	t.deadline = f.now.Add(d)
+	f.pending[t] = struct{}{}
+	f.cond.Broadcast()
+	return t
+}
+
+type fakeTimer struct {
+	clock    *Fake
+	deadline time.Time
+	period   time.Duration
+
+	ch chan time.Time
+	fn func()
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	if t.fn != nil {
+		return nil
+	}
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	f := t.clock
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	_, ok := f.pending[t]
+	delete(f.pending, t)
+	return ok
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	f := t.clock
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	_, ok := f.pending[t]
+	t.deadline = f.now.Add(d)
+	f.pending[t] = struct{}{}
+	f.cond.Broadcast()
+	return ok
+}
+
+type fakeTicker struct {
+	timer *fakeTimer
+}
+
+func (t fakeTicker) C() <-chan time.Time {
+	return t.timer.C()
+}
+
+func (t fakeTicker) Stop() {
+	t.timer.Stop()
+}
+
+// fire() is called with the clock locked.  Like the time
+// package, a pending tick is dropped if the last was not received.
+func (t *fakeTimer) fire() {
+	if t.fn != nil {
+		go t.fn()
+		return
+	}
+	select {
+	case t.ch <- t.deadline:
+	default:
+	}
+}
@@ -0,0 +1,66 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boz/kcache/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start)
+
+	timer := clk.NewTimer(time.Second)
+	ticker := clk.NewTicker(2 * time.Second)
+
+	calledch := make(chan time.Time, 1)
+	clk.AfterFunc(3*time.Second, func() { calledch <- clk.Now() })
+
+	clk.BlockUntil(3)
+
+	clk.Advance(999 * time.Millisecond)
+	assertNotFired(t, timer.C())
+
+	clk.Advance(time.Millisecond)
+	assert.Equal(t, start.Add(time.Second), <-timer.C())
+	assert.False(t, timer.Stop())
+
+	clk.Advance(2 * time.Second)
+	assert.Equal(t, start.Add(2*time.Second), <-ticker.C())
+	select {
+	case now := <-calledch:
+		assert.Equal(t, start.Add(3*time.Second), now)
+	case <-time.After(time.Second):
+		require.Fail(t, "not called")
+	}
+
+	// ticks which are not received are dropped.
+	clk.Advance(4 * time.Second)
+	assert.Equal(t, start.Add(4*time.Second), <-ticker.C())
+	assertNotFired(t, ticker.C())
+
+	assert.False(t, timer.Reset(time.Second))
+	assert.True(t, timer.Stop())
+	ticker.Stop()
+
+	clk.Advance(time.Minute)
+	assertNotFired(t, timer.C())
+	assertNotFired(t, ticker.C())
+
+	assert.Equal(t, time.Minute, clk.Since(start.Add(7*time.Second)))
+
+	assert.Equal(t, 0.5, clk.Float64())
+	clk.SetRandom(0.25)
+	assert.Equal(t, 0.25, clk.Float64())
+}
+
+func assertNotFired(t *testing.T, ch <-chan time.Time) {
+	select {
+	case <-ch:
+		assert.Fail(t, "fired")
+	default:
+	}
+}
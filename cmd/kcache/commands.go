@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/boz/kcache"
+	"github.com/boz/kcache/filter"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// runWatch() streams the events of a resource until interrupted.
+func runWatch(ctx context.Context, a *app, args []string) error {
+	opts := &options{}
+	fs := newFlagSet("watch", opts)
+	existing := fs.Bool("list", false, "print existing objects as create events before streaming")
+
+	args, err := opts.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := expectArgs(args, 1, "<resource>"); err != nil {
+		return err
+	}
+
+	r, err := lookupResource(args[0])
+	if err != nil {
+		return err
+	}
+	f, err := opts.parseFilter()
+	if err != nil {
+		return err
+	}
+	p, err := newPrinter(a.out, opts.output)
+	if err != nil {
+		return err
+	}
+
+	controller, err := a.controller(ctx, r, opts)
+	if err != nil {
+		return err
+	}
+	defer controller.Close()
+
+	sub, err := controller.SubscribeWithFilter(f)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	select {
+	case <-sub.Ready():
+	case <-sub.Done():
+		return controller.Error()
+	case <-ctx.Done():
+		return nil
+	}
+
+	if *existing {
+		objs, err := sub.Cache().List()
+		if err != nil {
+			return err
+		}
+		sortObjects(objs)
+		for _, obj := range objs {
+			if err := p.event(kcache.NewEvent(kcache.EventTypeCreate, obj)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for {
+		select {
+		case evt, ok := <-sub.Events():
+			if !ok {
+				return controller.Error()
+			}
+			if err := p.event(evt); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// runGet() prints the objects of a resource which match the
+// filter and, if given, the object names.
+func runGet(ctx context.Context, a *app, args []string) error {
+	opts := &options{}
+	fs := newFlagSet("get", opts)
+
+	args, err := opts.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := expectArgs(args, 1, "<resource>"); err != nil {
+		return err
+	}
+
+	r, err := lookupResource(args[0])
+	if err != nil {
+		return err
+	}
+	f, err := opts.parseFilter()
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		ids, err := parseNames(r, opts, args[1:])
+		if err != nil {
+			return err
+		}
+		f = filter.And(filter.NSName(ids...), f)
+	}
+	p, err := newPrinter(a.out, opts.output)
+	if err != nil {
+		return err
+	}
+
+	controller, err := a.controller(ctx, r, opts)
+	if err != nil {
+		return err
+	}
+	defer controller.Close()
+
+	objs, err := controller.Cache().List()
+	if err != nil {
+		return err
+	}
+
+	var result []metav1.Object
+	for _, obj := range objs {
+		if f.Accept(obj) {
+			result = append(result, obj)
+		}
+	}
+
+	if len(args) > 1 && len(result) == 0 {
+		return fmt.Errorf("no %v found", r.name)
+	}
+
+	sortObjects(result)
+	return p.list(result)
+}
+
+// runExplain() prints the explanation of a filter's result
+// for each object of a resource.
+func runExplain(ctx context.Context, a *app, args []string) error {
+	opts := &options{}
+	fs := newFlagSet("explain", opts)
+
+	args, err := opts.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := expectArgs(args, 2, "<resource> <filter>"); err != nil {
+		return err
+	}
+	if opts.filter != "" {
+		return usageErrorf("the filter is given as an argument")
+	}
+
+	r, err := lookupResource(args[0])
+	if err != nil {
+		return err
+	}
+	f, err := filter.Parse(args[1])
+	if err != nil {
+		return err
+	}
+	names := filter.Null()
+	if len(args) > 2 {
+		ids, err := parseNames(r, opts, args[2:])
+		if err != nil {
+			return err
+		}
+		names = filter.NSName(ids...)
+	}
+	p, err := newPrinter(a.out, opts.output)
+	if err != nil {
+		return err
+	}
+
+	controller, err := a.controller(ctx, r, opts)
+	if err != nil {
+		return err
+	}
+	defer controller.Close()
+
+	objs, err := controller.Cache().List()
+	if err != nil {
+		return err
+	}
+
+	var result []metav1.Object
+	for _, obj := range objs {
+		if names.Accept(obj) {
+			result = append(result, obj)
+		}
+	}
+
+	if len(args) > 2 && len(result) == 0 {
+		return fmt.Errorf("no %v found", r.name)
+	}
+
+	sortObjects(result)
+	return p.explanations(result, f)
+}
+
+// runJoin() prints the target objects joined to the source objects
+// which match the filter.  With -watch it then streams the events
+// of the joined objects.
+func runJoin(ctx context.Context, a *app, args []string) error {
+	opts := &options{}
+	fs := newFlagSet("join", opts)
+	stream := fs.Bool("watch", false, "stream events after printing the joined objects")
+
+	args, err := opts.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := expectArgs(args, 2, "<source> <target>"); err != nil {
+		return err
+	}
+
+	src, err := lookupResource(args[0])
+	if err != nil {
+		return err
+	}
+	dst, err := lookupResource(args[1])
+	if err != nil {
+		return err
+	}
+	spec, err := lookupJoin(src, dst)
+	if err != nil {
+		return err
+	}
+	f, err := opts.parseFilter()
+	if err != nil {
+		return err
+	}
+	p, err := newPrinter(a.out, opts.output)
+	if err != nil {
+		return err
+	}
+
+	// the joined controllers run until ctx is done.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	j := &joiner{a, opts, f}
+	controller, err := spec.run(ctx, j)
+	if err != nil {
+		return err
+	}
+	if err := waitReady(ctx, controller); err != nil {
+		return err
+	}
+
+	var sub kcache.Subscription
+	if *stream {
+		if sub, err = controller.Subscribe(); err != nil {
+			return err
+		}
+		defer sub.Close()
+	}
+
+	objs, err := controller.Cache().List()
+	if err != nil {
+		return err
+	}
+	sortObjects(objs)
+	if err := p.list(objs); err != nil {
+		return err
+	}
+
+	if sub == nil {
+		return nil
+	}
+
+	for {
+		select {
+		case evt, ok := <-sub.Events():
+			if !ok {
+				return controller.Error()
+			}
+			if err := p.event(evt); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func sortObjects(objs []metav1.Object) {
+	sort.Slice(objs, func(i, j int) bool {
+		if objs[i].GetNamespace() != objs[j].GetNamespace() {
+			return objs[i].GetNamespace() < objs[j].GetNamespace()
+		}
+		return objs[i].GetName() < objs[j].GetName()
+	})
+}
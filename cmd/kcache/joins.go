@@ -0,0 +1,362 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	logutil "github.com/boz/go-logutil"
+	"github.com/boz/kcache"
+	"github.com/boz/kcache/client"
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/join"
+	"github.com/boz/kcache/types/cronjob"
+	"github.com/boz/kcache/types/daemonset"
+	"github.com/boz/kcache/types/deployment"
+	"github.com/boz/kcache/types/event"
+	"github.com/boz/kcache/types/ingress"
+	"github.com/boz/kcache/types/job"
+	"github.com/boz/kcache/types/node"
+	"github.com/boz/kcache/types/pod"
+	"github.com/boz/kcache/types/replicaset"
+	"github.com/boz/kcache/types/replicationcontroller"
+	"github.com/boz/kcache/types/service"
+	"github.com/boz/kcache/types/statefulset"
+)
+
+// joiner creates the controllers of a join.  The label selector
+// and filter apply to the source resource only.
+type joiner struct {
+	app    *app
+	opts   *options
+	filter filter.Filter
+}
+
+func (j *joiner) log() logutil.Log {
+	return j.app.log
+}
+
+func (j *joiner) source(name string) client.Client {
+	r, _ := lookupResource(name)
+	return j.app.client(r, j.opts)
+}
+
+func (j *joiner) target(name string) client.Client {
+	r, _ := lookupResource(name)
+	opts := *j.opts
+	opts.selector = ""
+	return j.app.client(r, &opts)
+}
+
+type joinSpec struct {
+	source string
+	target string
+	run    func(context.Context, *joiner) (kcache.Controller, error)
+}
+
+var joins = []*joinSpec{
+	{"services", "pods", func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+		src, err := sourceServices(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := pod.BuildController(ctx, j.log(), j.target("pods"))
+		if err != nil {
+			return nil, err
+		}
+		return untyped(join.ServicePods(ctx, src, dst))
+	}},
+	{"replicationcontrollers", "pods", func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+		src, err := sourceRCs(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := pod.BuildController(ctx, j.log(), j.target("pods"))
+		if err != nil {
+			return nil, err
+		}
+		return untyped(join.RCPods(ctx, src, dst))
+	}},
+	{"replicasets", "pods", func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+		src, err := sourceReplicaSets(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := pod.BuildController(ctx, j.log(), j.target("pods"))
+		if err != nil {
+			return nil, err
+		}
+		return untyped(join.RSPods(ctx, src, dst))
+	}},
+	{"deployments", "pods", func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+		src, err := sourceDeployments(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := pod.BuildController(ctx, j.log(), j.target("pods"))
+		if err != nil {
+			return nil, err
+		}
+		return untyped(join.DeploymentPods(ctx, src, dst))
+	}},
+	{"deployments", "replicasets", func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+		src, err := sourceDeployments(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := replicaset.BuildController(ctx, j.log(), j.target("replicasets"))
+		if err != nil {
+			return nil, err
+		}
+		return untyped(join.DeploymentReplicaSets(ctx, src, dst))
+	}},
+	{"statefulsets", "pods", func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+		src, err := sourceStatefulSets(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := pod.BuildController(ctx, j.log(), j.target("pods"))
+		if err != nil {
+			return nil, err
+		}
+		return untyped(join.StatefulSetPods(ctx, src, dst))
+	}},
+	{"daemonsets", "pods", func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+		src, err := sourceDaemonSets(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := pod.BuildController(ctx, j.log(), j.target("pods"))
+		if err != nil {
+			return nil, err
+		}
+		return untyped(join.DaemonSetPods(ctx, src, dst))
+	}},
+	{"jobs", "pods", func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+		src, err := sourceJobs(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := pod.BuildController(ctx, j.log(), j.target("pods"))
+		if err != nil {
+			return nil, err
+		}
+		return untyped(join.JobPods(ctx, src, dst))
+	}},
+	{"cronjobs", "jobs", func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+		src, err := sourceCronJobs(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := job.BuildController(ctx, j.log(), j.target("jobs"))
+		if err != nil {
+			return nil, err
+		}
+		return untyped(join.CronJobJobs(ctx, src, dst))
+	}},
+	{"nodes", "pods", func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+		src, err := sourceNodes(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := pod.BuildController(ctx, j.log(), j.target("pods"))
+		if err != nil {
+			return nil, err
+		}
+		return untyped(join.NodePods(ctx, src, dst))
+	}},
+	{"ingresses", "services", func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+		src, err := sourceIngresses(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := service.BuildController(ctx, j.log(), j.target("services"))
+		if err != nil {
+			return nil, err
+		}
+		return untyped(join.IngressServices(ctx, src, dst))
+	}},
+	{"ingresses", "pods", func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+		src, err := sourceIngresses(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		svcs, err := service.BuildController(ctx, j.log(), j.target("services"))
+		if err != nil {
+			return nil, err
+		}
+		dst, err := pod.BuildController(ctx, j.log(), j.target("pods"))
+		if err != nil {
+			return nil, err
+		}
+		return untyped(join.IngressPods(ctx, src, svcs, dst))
+	}},
+	{"pods", "services", func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+		src, err := sourcePods(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := service.BuildController(ctx, j.log(), j.target("services"))
+		if err != nil {
+			return nil, err
+		}
+		return untyped(join.PodServices(ctx, src, dst))
+	}},
+	{"pods", "deployments", func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+		src, err := sourcePods(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := deployment.BuildController(ctx, j.log(), j.target("deployments"))
+		if err != nil {
+			return nil, err
+		}
+		return untyped(join.PodDeployments(ctx, src, dst))
+	}},
+	{"pods", "nodes", func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+		src, err := sourcePods(ctx, j)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := node.BuildController(ctx, j.log(), j.target("nodes"))
+		if err != nil {
+			return nil, err
+		}
+		return untyped(join.PodNodes(ctx, src, dst))
+	}},
+}
+
+// lookupJoin() returns the join from src to dst.  Every
+// resource can be joined to its events.
+func lookupJoin(src, dst *resource) (*joinSpec, error) {
+	for _, spec := range joins {
+		if spec.source == src.name && spec.target == dst.name {
+			return spec, nil
+		}
+	}
+	if dst.name == "events" && src.name != "events" {
+		return &joinSpec{src.name, dst.name, func(ctx context.Context, j *joiner) (kcache.Controller, error) {
+			return joinObjectEvents(ctx, j, src)
+		}}, nil
+	}
+
+	var names []string
+	for _, spec := range joins {
+		if spec.source == src.name {
+			names = append(names, spec.target)
+		}
+	}
+	names = append(names, "events")
+	return nil, fmt.Errorf("cannot join %v to %v (expected one of: %v)", src.name, dst.name, strings.Join(names, ", "))
+}
+
+func joinObjectEvents(ctx context.Context, j *joiner, src *resource) (kcache.Controller, error) {
+	base, err := kcache.NewController(ctx, j.log(), j.source(src.name))
+	if err != nil {
+		return nil, err
+	}
+	srcf, err := base.CloneWithFilter(j.filter)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := event.BuildController(ctx, j.log(), j.target("events"))
+	if err != nil {
+		return nil, err
+	}
+	return untyped(join.ObjectEventsWith(ctx, srcf, dst))
+}
+
+func sourceServices(ctx context.Context, j *joiner) (service.Controller, error) {
+	src, err := service.BuildController(ctx, j.log(), j.source("services"))
+	if err != nil {
+		return nil, err
+	}
+	return src.CloneWithFilter(j.filter)
+}
+
+func sourceDeployments(ctx context.Context, j *joiner) (deployment.Controller, error) {
+	src, err := deployment.BuildController(ctx, j.log(), j.source("deployments"))
+	if err != nil {
+		return nil, err
+	}
+	return src.CloneWithFilter(j.filter)
+}
+
+func sourceIngresses(ctx context.Context, j *joiner) (ingress.Controller, error) {
+	src, err := ingress.BuildController(ctx, j.log(), j.source("ingresses"))
+	if err != nil {
+		return nil, err
+	}
+	return src.CloneWithFilter(j.filter)
+}
+
+func sourcePods(ctx context.Context, j *joiner) (pod.Controller, error) {
+	src, err := pod.BuildController(ctx, j.log(), j.source("pods"))
+	if err != nil {
+		return nil, err
+	}
+	return src.CloneWithFilter(j.filter)
+}
+
+func sourceRCs(ctx context.Context, j *joiner) (replicationcontroller.Controller, error) {
+	src, err := replicationcontroller.BuildController(ctx, j.log(), j.source("replicationcontrollers"))
+	if err != nil {
+		return nil, err
+	}
+	return src.CloneWithFilter(j.filter)
+}
+
+func sourceReplicaSets(ctx context.Context, j *joiner) (replicaset.Controller, error) {
+	src, err := replicaset.BuildController(ctx, j.log(), j.source("replicasets"))
+	if err != nil {
+		return nil, err
+	}
+	return src.CloneWithFilter(j.filter)
+}
+
+func sourceStatefulSets(ctx context.Context, j *joiner) (statefulset.Controller, error) {
+	src, err := statefulset.BuildController(ctx, j.log(), j.source("statefulsets"))
+	if err != nil {
+		return nil, err
+	}
+	return src.CloneWithFilter(j.filter)
+}
+
+func sourceDaemonSets(ctx context.Context, j *joiner) (daemonset.Controller, error) {
+	src, err := daemonset.BuildController(ctx, j.log(), j.source("daemonsets"))
+	if err != nil {
+		return nil, err
+	}
+	return src.CloneWithFilter(j.filter)
+}
+
+func sourceJobs(ctx context.Context, j *joiner) (job.Controller, error) {
+	src, err := job.BuildController(ctx, j.log(), j.source("jobs"))
+	if err != nil {
+		return nil, err
+	}
+	return src.CloneWithFilter(j.filter)
+}
+
+func sourceCronJobs(ctx context.Context, j *joiner) (cronjob.Controller, error) {
+	src, err := cronjob.BuildController(ctx, j.log(), j.source("cronjobs"))
+	if err != nil {
+		return nil, err
+	}
+	return src.CloneWithFilter(j.filter)
+}
+
+func sourceNodes(ctx context.Context, j *joiner) (node.Controller, error) {
+	src, err := node.BuildController(ctx, j.log(), j.source("nodes"))
+	if err != nil {
+		return nil, err
+	}
+	return src.CloneWithFilter(j.filter)
+}
+
+func untyped[C join.Untyped](c C, err error) (kcache.Controller, error) {
+	if err != nil {
+		return nil, err
+	}
+	return c.Untyped(), nil
+}
@@ -0,0 +1,163 @@
+// Command kcache watches and queries kubernetes resources
+// with kcache controllers.
+//
+//	kcache watch -n default -o json pods
+//	kcache list -filter 'label(app=web)' deployments
+//	kcache get pods kube-system/kube-dns-1234
+//	kcache join -filter 'name=web' services pods
+//	kcache explain pods 'ns=default and label(app=web)'
+//
+// The kubeconfig is located as by util.KubeConfig(); the
+// -context flag selects a context other than the current one.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+
+	logutil "github.com/boz/go-logutil"
+	lr "github.com/boz/go-logutil/logrus"
+	"github.com/boz/kcache/client"
+	"github.com/boz/kcache/util"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type command struct {
+	usage string
+	help  string
+	run   func(context.Context, *app, []string) error
+}
+
+var commands = map[string]*command{
+	"watch": {
+		usage: "watch [flags] <resource>",
+		help:  "stream events for a resource",
+		run:   runWatch,
+	},
+	"get": {
+		usage: "get [flags] <resource> [[<namespace>/]<name>...]",
+		help:  "print objects by name",
+		run:   runGet,
+	},
+	"list": {
+		usage: "list [flags] <resource>",
+		help:  "print all objects of a resource",
+		run:   runGet,
+	},
+	"join": {
+		usage: "join [flags] <source> <target>",
+		help:  "print the target objects joined to the source objects",
+		run:   runJoin,
+	},
+	"explain": {
+		usage: "explain [flags] <resource> <filter> [[<namespace>/]<name>...]",
+		help:  "explain why objects are accepted or rejected by a filter",
+		run:   runExplain,
+	},
+}
+
+// usageError is returned by commands invoked with bad arguments.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+func usageErrorf(format string, args ...interface{}) error {
+	return usageError{fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	kubecontext := flag.String("context", "", "kubeconfig context to use")
+	verbose := flag.Bool("v", false, "log controller activity")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := flag.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "kcache: unknown command %q\n", name)
+		usage()
+		os.Exit(2)
+	}
+
+	logger := logrus.New()
+	logger.Level = logrus.WarnLevel
+	if *verbose {
+		logger.Level = logrus.DebugLevel
+	}
+	log := lr.New(logger)
+
+	cs, _, err := util.KubeClient(&clientcmd.ConfigOverrides{CurrentContext: *kubecontext})
+	if err != nil {
+		log.ErrFatal(err, "kubernetes client")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	a := &app{
+		out: os.Stdout,
+		log: log,
+		clients: func(r *resource, ns string) client.Client {
+			return r.client(cs, ns)
+		},
+	}
+
+	err = cmd.run(ctx, a, flag.Args()[1:])
+	var uerr usageError
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+		os.Exit(2)
+	case errors.As(err, &uerr):
+		fmt.Fprintf(os.Stderr, "kcache %v: %v\nusage: kcache %v\n", name, err, cmd.usage)
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "kcache %v: %v\n", name, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "usage: kcache [-context <context>] [-v] <command> [flags] <args>\n\ncommands:\n")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-8v %v\n", name, commands[name].help)
+	}
+
+	fmt.Fprintf(out, "\nresources:\n")
+	for _, name := range resourceNames() {
+		fmt.Fprintf(out, "  %v\n", name)
+	}
+}
+
+// app holds what commands need from their environment.
+type app struct {
+	out io.Writer
+	log logutil.Log
+
+	// clients returns a client for r in namespace ns.
+	clients func(r *resource, ns string) client.Client
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logutil "github.com/boz/go-logutil"
+	"github.com/boz/kcache/client"
+	"github.com/boz/kcache/kcachetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+func TestGet(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := testServer(t)
+	a, out := testApp(server)
+
+	require.NoError(t, runGet(ctx, a, []string{"pods", "-filter", "label(app=web)"}))
+	assert.Equal(t, "NAMESPACE  NAME   VERSION\na          web-1  1\nb          web-2  3\n", out.String())
+
+	out.Reset()
+	require.NoError(t, runGet(ctx, a, []string{"-o", "json", "po", "a/web-1", "web-2", "-n", "b"}))
+
+	var list struct {
+		Items []corev1.Pod `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(out.bytes(), &list))
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "Pod", list.Items[0].Kind)
+	assert.Equal(t, "web-2", list.Items[0].Name)
+
+	assert.EqualError(t, runGet(ctx, a, []string{"pods", "a/web-2"}), "no pods found")
+	assert.Error(t, runGet(ctx, a, []string{"widgets"}))
+	assert.Error(t, runGet(ctx, a, []string{"-o", "xml", "pods"}))
+	assert.IsType(t, usageError{}, runGet(ctx, a, []string{"-l", "app in (", "pods"}))
+	assert.IsType(t, usageError{}, runGet(ctx, a, nil))
+}
+
+func TestGet_selector(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := testServer(t)
+	a, _ := testApp(server)
+
+	var selectors []string
+	var mtx sync.Mutex
+	clients := a.clients
+	a.clients = func(r *resource, ns string) client.Client {
+		c := clients(r, ns)
+		return client.NewClient(
+			func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+				mtx.Lock()
+				selectors = append(selectors, opts.LabelSelector)
+				mtx.Unlock()
+				return c.List(ctx, opts)
+			}, c.Watch)
+	}
+
+	require.NoError(t, runGet(ctx, a, []string{"-l", "app=web", "pods"}))
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	assert.Equal(t, []string{"app=web"}, selectors)
+}
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := testServer(t)
+	a, out := testApp(server)
+
+	donech := make(chan error, 1)
+	go func() {
+		donech <- runWatch(ctx, a, []string{"-o", "yaml", "-list", "-n", "a", "pods"})
+	}()
+
+	assert.Eventually(t, func() bool {
+		return strings.Count(out.String(), "---\n") == 2
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, server.Create(testGenPod("a", "web-3", map[string]string{"app": "web"})))
+	require.NoError(t, server.Create(testGenPod("b", "web-4", map[string]string{"app": "web"})))
+
+	assert.Eventually(t, func() bool {
+		return strings.Count(out.String(), "---\n") == 3
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-donech:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		require.Fail(t, "watch did not return")
+	}
+
+	docs := strings.Split(out.String(), "---\n")[1:]
+	require.Len(t, docs, 3)
+
+	var names []string
+	for _, doc := range docs {
+		var evt struct {
+			Type   string     `json:"type"`
+			Object corev1.Pod `json:"object"`
+		}
+		require.NoError(t, yaml.Unmarshal([]byte(doc), &evt))
+		assert.Equal(t, "create", evt.Type)
+		names = append(names, evt.Object.Name)
+	}
+	assert.Equal(t, []string{"db-1", "web-1", "web-3"}, names)
+}
+
+func TestJoin(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := testServer(t)
+	require.NoError(t, server.Create(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "web"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+	}))
+	require.NoError(t, server.Create(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "db"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "db"}},
+	}))
+
+	a, out := testApp(server)
+
+	require.NoError(t, runJoin(ctx, a, []string{"svc", "pods", "-filter", "name=web"}))
+	assert.Equal(t, "NAMESPACE  NAME   VERSION\na          web-1  1\n", out.String())
+
+	out.Reset()
+	require.NoError(t, runJoin(ctx, a, []string{"services", "pods"}))
+	assert.Equal(t, "NAMESPACE  NAME   VERSION\na          db-1   2\na          web-1  1\n", out.String())
+
+	assert.Error(t, runJoin(ctx, a, []string{"configmaps", "pods"}))
+}
+
+func TestExplain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := testServer(t)
+	a, out := testApp(server)
+
+	require.NoError(t, runExplain(ctx, a, []string{"pods", "ns=a and label(app=web)", "db-1", "web-1"}))
+	assert.Equal(t, strings.Join([]string{
+		"a/db-1:",
+		"  rejected: ns=a and label(app=web) (1 of 2 rejected)",
+		"    accepted: ns=a",
+		"    rejected: label(app=web) (labels: {app=db})",
+		"      rejected: app=web",
+		"a/web-1:",
+		"  accepted: ns=a and label(app=web)",
+		"    accepted: ns=a",
+		"    accepted: label(app=web)",
+		"",
+	}, "\n"), out.String())
+
+	assert.IsType(t, usageError{}, runExplain(ctx, a, []string{"pods"}))
+}
+
+func testServer(t *testing.T) *kcachetest.Server {
+	server := kcachetest.NewServer()
+	require.NoError(t, server.Create(testGenPod("a", "web-1", map[string]string{"app": "web"})))
+	require.NoError(t, server.Create(testGenPod("a", "db-1", map[string]string{"app": "db"})))
+	require.NoError(t, server.Create(testGenPod("b", "web-2", map[string]string{"app": "web"})))
+	return server
+}
+
+func testApp(server *kcachetest.Server) (*app, *testBuffer) {
+	out := &testBuffer{}
+	return &app{
+		out: out,
+		log: logutil.Default(),
+		clients: func(r *resource, ns string) client.Client {
+			return server.Client(r.object, ns)
+		},
+	}, out
+}
+
+func testGenPod(ns, name string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+			Labels:    labels,
+		},
+	}
+}
+
+type testBuffer struct {
+	buf bytes.Buffer
+	mtx sync.Mutex
+}
+
+func (b *testBuffer) Write(p []byte) (int, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *testBuffer) String() string {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.buf.String()
+}
+
+func (b *testBuffer) bytes() []byte {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func (b *testBuffer) Reset() {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.buf.Reset()
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/boz/kcache"
+	"github.com/boz/kcache/client"
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// options are the flags common to all commands.
+type options struct {
+	namespace string
+	selector  string
+	filter    string
+	output    string
+}
+
+func newFlagSet(name string, opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet("kcache "+name, flag.ContinueOnError)
+	fs.StringVar(&opts.namespace, "n", metav1.NamespaceAll, "namespace (default all namespaces)")
+	fs.StringVar(&opts.selector, "l", "", "label selector sent to the API server")
+	fs.StringVar(&opts.filter, "filter", "", "filter expression applied to the cache")
+	fs.StringVar(&opts.output, "o", formatText, "output format: text, json or yaml")
+	return fs
+}
+
+// parse() parses args with fs, allowing flags to follow
+// positional arguments.  It returns the positional arguments.
+func (opts *options) parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if _, err := labels.Parse(opts.selector); err != nil {
+		return nil, usageErrorf("invalid label selector: %v", err)
+	}
+	return positional, nil
+}
+
+// parseFilter() returns the filter given by -filter, or a filter
+// which accepts everything if there is none.
+func (opts *options) parseFilter() (filter.ComparableFilter, error) {
+	if opts.filter == "" {
+		return filter.Null(), nil
+	}
+	return filter.Parse(opts.filter)
+}
+
+// client() returns a client for r limited to the namespace
+// and label selector of opts.
+func (a *app) client(r *resource, opts *options) client.Client {
+	ns := opts.namespace
+	if !r.namespaced {
+		ns = metav1.NamespaceAll
+	}
+	c := a.clients(r, ns)
+
+	if opts.selector == "" {
+		return c
+	}
+
+	selector := opts.selector
+	return client.NewClient(
+		func(ctx context.Context, lopts metav1.ListOptions) (runtime.Object, error) {
+			lopts.LabelSelector = selector
+			return c.List(ctx, lopts)
+		},
+		func(ctx context.Context, lopts metav1.ListOptions) (watch.Interface, error) {
+			lopts.LabelSelector = selector
+			return c.Watch(ctx, lopts)
+		})
+}
+
+// controller() creates a controller for r and waits for it to be ready.
+func (a *app) controller(ctx context.Context, r *resource, opts *options) (kcache.Controller, error) {
+	controller, err := kcache.NewController(ctx, a.log, a.client(r, opts))
+	if err != nil {
+		return nil, err
+	}
+	if err := waitReady(ctx, controller); err != nil {
+		controller.Close()
+		return nil, err
+	}
+	return controller, nil
+}
+
+// waitReady() waits until controller is ready, closed or ctx is done.
+func waitReady(ctx context.Context, controller kcache.Controller) error {
+	select {
+	case <-controller.Ready():
+		return nil
+	case <-controller.Done():
+		if err := controller.Error(); err != nil {
+			return err
+		}
+		return kcache.ErrNotRunning
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// parseNames() parses object names, which are qualified by the
+// namespace of opts if they do not include one.  Unqualified
+// names match objects in any namespace.
+func parseNames(r *resource, opts *options, args []string) ([]nsname.NSName, error) {
+	ids := make([]nsname.NSName, 0, len(args))
+	for _, arg := range args {
+		id, err := nsname.Parse(arg)
+		if err != nil {
+			id = nsname.New(opts.namespace, arg)
+		}
+		if !r.namespaced {
+			id.Namespace = ""
+		}
+		if id.Name == "" {
+			return nil, usageErrorf("%v: name required", arg)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func expectArgs(args []string, min int, names string) error {
+	if len(args) < min {
+		return usageErrorf("expected %v", names)
+	}
+	return nil
+}
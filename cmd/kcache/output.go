@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/boz/kcache"
+	"github.com/boz/kcache/filter"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+// printer writes objects, events and explanations in one of
+// the output formats.  JSON events are written one per line
+// and YAML events as separate documents so that they can be
+// consumed as they arrive.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	switch format {
+	case formatText, formatJSON, formatYAML:
+		return &printer{w, format}, nil
+	default:
+		return nil, usageErrorf("unknown output format %q", format)
+	}
+}
+
+type eventOutput struct {
+	Type   kcache.EventType `json:"type"`
+	Object interface{}      `json:"object"`
+}
+
+type listOutput struct {
+	APIVersion string        `json:"apiVersion"`
+	Kind       string        `json:"kind"`
+	Items      []interface{} `json:"items"`
+}
+
+type explainOutput struct {
+	Object      string             `json:"object"`
+	Explanation filter.Explanation `json:"explanation"`
+}
+
+func (p *printer) event(evt kcache.Event) error {
+	obj := evt.Resource()
+	if p.format == formatText {
+		_, err := fmt.Fprintf(p.w, "%-6v %v %v\n", evt.Type(), objectName(obj), obj.GetResourceVersion())
+		return err
+	}
+	return p.encode(eventOutput{evt.Type(), withKind(obj)}, true)
+}
+
+func (p *printer) list(objs []metav1.Object) error {
+	if p.format == formatText {
+		tw := tabwriter.NewWriter(p.w, 0, 8, 2, ' ', 0)
+		fmt.Fprintf(tw, "NAMESPACE\tNAME\tVERSION\n")
+		for _, obj := range objs {
+			fmt.Fprintf(tw, "%v\t%v\t%v\n", obj.GetNamespace(), obj.GetName(), obj.GetResourceVersion())
+		}
+		return tw.Flush()
+	}
+
+	items := make([]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		items = append(items, withKind(obj))
+	}
+	return p.encode(listOutput{"v1", "List", items}, false)
+}
+
+func (p *printer) explanations(objs []metav1.Object, f filter.Filter) error {
+	if p.format == formatText {
+		for _, obj := range objs {
+			if _, err := fmt.Fprintf(p.w, "%v:\n%v", objectName(obj), indent(filter.Explain(f, obj).String())); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	items := make([]explainOutput, 0, len(objs))
+	for _, obj := range objs {
+		items = append(items, explainOutput{objectName(obj), filter.Explain(f, obj)})
+	}
+	return p.encode(items, false)
+}
+
+func (p *printer) encode(v interface{}, stream bool) error {
+	var buf []byte
+	var err error
+
+	switch {
+	case p.format == formatYAML:
+		buf, err = yaml.Marshal(v)
+		if stream {
+			buf = append([]byte("---\n"), buf...)
+		}
+	case stream:
+		buf, err = json.Marshal(v)
+		buf = append(buf, '\n')
+	default:
+		buf, err = json.MarshalIndent(v, "", "  ")
+		buf = append(buf, '\n')
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = p.w.Write(buf)
+	return err
+}
+
+// withKind() returns a copy of obj with its kind set so that
+// it can be decoded by other tools.
+func withKind(obj metav1.Object) interface{} {
+	robj, ok := obj.(runtime.Object)
+	if !ok {
+		return obj
+	}
+	gvks, _, err := scheme.Scheme.ObjectKinds(robj)
+	if err != nil || len(gvks) == 0 {
+		return obj
+	}
+	robj = robj.DeepCopyObject()
+	robj.GetObjectKind().SetGroupVersionKind(gvks[0])
+	return robj
+}
+
+func objectName(obj metav1.Object) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+func indent(s string) string {
+	var out []byte
+	start := true
+	for i := 0; i < len(s); i++ {
+		if start {
+			out = append(out, "  "...)
+		}
+		out = append(out, s[i])
+		start = s[i] == '\n'
+	}
+	return string(out)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/boz/kcache/client"
+	"github.com/boz/kcache/types/configmap"
+	"github.com/boz/kcache/types/cronjob"
+	"github.com/boz/kcache/types/daemonset"
+	"github.com/boz/kcache/types/deployment"
+	"github.com/boz/kcache/types/endpoints"
+	"github.com/boz/kcache/types/endpointslice"
+	"github.com/boz/kcache/types/event"
+	"github.com/boz/kcache/types/horizontalpodautoscaler"
+	"github.com/boz/kcache/types/ingress"
+	"github.com/boz/kcache/types/job"
+	"github.com/boz/kcache/types/namespace"
+	"github.com/boz/kcache/types/node"
+	"github.com/boz/kcache/types/persistentvolume"
+	"github.com/boz/kcache/types/persistentvolumeclaim"
+	"github.com/boz/kcache/types/pod"
+	"github.com/boz/kcache/types/replicaset"
+	"github.com/boz/kcache/types/replicationcontroller"
+	"github.com/boz/kcache/types/secret"
+	"github.com/boz/kcache/types/service"
+	"github.com/boz/kcache/types/serviceaccount"
+	"github.com/boz/kcache/types/statefulset"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+// resource describes a kind that the typed packages can watch.
+type resource struct {
+	name       string
+	aliases    []string
+	namespaced bool
+
+	// object is an empty instance of the kind.
+	object runtime.Object
+
+	client func(kubernetes.Interface, string) client.Client
+}
+
+var resources = []*resource{
+	{"configmaps", []string{"configmap", "cm"}, true, &corev1.ConfigMap{}, configmap.NewClient},
+	{"cronjobs", []string{"cronjob", "cj"}, true, &batchv1.CronJob{}, cronjob.NewClient},
+	{"daemonsets", []string{"daemonset", "ds"}, true, &appsv1.DaemonSet{}, daemonset.NewClient},
+	{"deployments", []string{"deployment", "deploy"}, true, &appsv1.Deployment{}, deployment.NewClient},
+	{"endpoints", []string{"ep"}, true, &corev1.Endpoints{}, endpoints.NewClient},
+	{"endpointslices", []string{"endpointslice"}, true, &discoveryv1.EndpointSlice{}, endpointslice.NewClient},
+	{"events", []string{"event", "ev"}, true, &corev1.Event{}, event.NewClient},
+	{"horizontalpodautoscalers", []string{"horizontalpodautoscaler", "hpa"}, true, &autoscalingv1.HorizontalPodAutoscaler{}, horizontalpodautoscaler.NewClient},
+	{"ingresses", []string{"ingress", "ing"}, true, &networkingv1.Ingress{}, ingress.NewClient},
+	{"jobs", []string{"job"}, true, &batchv1.Job{}, job.NewClient},
+	{"namespaces", []string{"namespace", "ns"}, false, &corev1.Namespace{}, namespace.NewClient},
+	{"nodes", []string{"node", "no"}, false, &corev1.Node{}, node.NewClient},
+	{"persistentvolumes", []string{"persistentvolume", "pv"}, false, &corev1.PersistentVolume{}, persistentvolume.NewClient},
+	{"persistentvolumeclaims", []string{"persistentvolumeclaim", "pvc"}, true, &corev1.PersistentVolumeClaim{}, persistentvolumeclaim.NewClient},
+	{"pods", []string{"pod", "po"}, true, &corev1.Pod{}, pod.NewClient},
+	{"replicasets", []string{"replicaset", "rs"}, true, &appsv1.ReplicaSet{}, replicaset.NewClient},
+	{"replicationcontrollers", []string{"replicationcontroller", "rc"}, true, &corev1.ReplicationController{}, replicationcontroller.NewClient},
+	{"secrets", []string{"secret"}, true, &corev1.Secret{}, secret.NewClient},
+	{"services", []string{"service", "svc"}, true, &corev1.Service{}, service.NewClient},
+	{"serviceaccounts", []string{"serviceaccount", "sa"}, true, &corev1.ServiceAccount{}, serviceaccount.NewClient},
+	{"statefulsets", []string{"statefulset", "sts"}, true, &appsv1.StatefulSet{}, statefulset.NewClient},
+}
+
+// lookupResource() finds the resource with the given name or alias.
+func lookupResource(name string) (*resource, error) {
+	name = strings.ToLower(name)
+	for _, r := range resources {
+		if r.name == name {
+			return r, nil
+		}
+		for _, alias := range r.aliases {
+			if alias == name {
+				return r, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown resource %q", name)
+}
+
+func resourceNames() []string {
+	names := make([]string, 0, len(resources))
+	for _, r := range resources {
+		names = append(names, r.name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -3,6 +3,7 @@ package kcache
 import (
 	"context"
 	builtin_errors "errors"
+	"time"
 
 	lifecycle "github.com/boz/go-lifecycle"
 	logutil "github.com/boz/go-logutil"
@@ -12,7 +13,16 @@ import (
 )
 
 var (
-	ErrNotRunning = builtin_errors.New("Not running")
+	ErrNotRunning   = builtin_errors.New("Not running")
+	ErrUnknownIndex = builtin_errors.New("Unknown index")
+
+	// ErrVersionTooOld is returned by SubscribeFrom() when events
+	// after the requested version are no longer journaled.
+	ErrVersionTooOld = builtin_errors.New("Version too old")
+
+	// ErrNoJournal is returned by SubscribeFrom() on controllers
+	// created without a journal.
+	ErrNoJournal = builtin_errors.New("No journal")
 )
 
 type Publisher interface {
@@ -32,6 +42,13 @@ type CacheController interface {
 type Controller interface {
 	CacheController
 	Publisher
+
+	// SubscribeFrom() creates a subscription which first receives the
+	// journaled events after version, then live events.  Events produced
+	// by a list are journaled at the version of the list, so they may be
+	// received again.
+	SubscribeFrom(version string) (Subscription, error)
+
 	Done() <-chan struct{}
 	Close()
 	Error() error
@@ -55,7 +72,17 @@ type controller struct {
 	cache   cache
 
 	subscription subscription
-	publisher    Publisher
+	publisher    Controller
+
+	// journal is nil unless enabled.
+	journal *journal
+
+	// snapshot is nil unless snapshots are enabled.
+	snapshot *snapshotter
+	warm     *warmStart
+
+	env  environment
+	node *topologyNode
 
 	log logutil.Log
 	lc  lifecycle.Lifecycle
@@ -82,10 +109,18 @@ func (c *controller) Cache() CacheReader {
 	return c.cache
 }
 
+func (c *controller) topologyNode() *topologyNode {
+	return c.node
+}
+
 func (c *controller) Subscribe() (Subscription, error) {
 	return c.publisher.Subscribe()
 }
 
+func (c *controller) SubscribeFrom(version string) (Subscription, error) {
+	return c.publisher.SubscribeFrom(version)
+}
+
 func (c *controller) SubscribeWithFilter(f filter.Filter) (FilterSubscription, error) {
 	return c.publisher.SubscribeWithFilter(f)
 }
@@ -110,6 +145,26 @@ func (c *controller) run() {
 	defer c.lc.ShutdownCompleted()
 	initialized := false
 
+	var snapch <-chan time.Time
+	if c.snapshot != nil && c.snapshot.period > 0 {
+		ticker := c.env.clock.NewTicker(c.snapshot.period)
+		defer ticker.Stop()
+		snapch = ticker.C()
+	}
+
+	if c.warm != nil {
+		if err := c.warmStart(); err != nil {
+			c.log.Errorf("warm start error: %v", err)
+			c.lc.ShutdownInitiated(errors.Wrap(err, "warm start"))
+		} else {
+			c.log.Debugf("ready (warm start: version %v)", c.warm.version)
+			c.startJournal(c.warm.version)
+			initialized = true
+			close(c.readych)
+		}
+		c.warm = nil
+	}
+
 mainloop:
 	for {
 		select {
@@ -117,9 +172,23 @@ mainloop:
 		case err := <-c.lc.ShutdownRequest():
 
 			c.log.Debugf("shutdown request: %v", err)
+			if initialized {
+				c.writeSnapshot()
+			}
 			c.lc.ShutdownInitiated(err)
 			break mainloop
 
+		case <-snapch:
+
+			if initialized {
+				c.writeSnapshot()
+			}
+
+		case <-c.watcher.expired():
+
+			c.log.Debugf("watch version expired; relisting")
+			c.lister.refresh()
+
 		case <-c.lister.Done():
 
 			err := c.lister.Error()
@@ -149,14 +218,14 @@ mainloop:
 				break mainloop
 			}
 
-			version, err := listResourceVersion(result.list)
+			listVersion, err := listResourceVersion(result.list)
 			if err != nil {
 				c.log.Errorf("resource version error: %v", err)
 				c.lc.ShutdownInitiated(errors.Wrap(err, "listing resource version"))
 				break mainloop
 			}
 
-			c.log.Debugf("list version: %v", version)
+			c.log.Debugf("list version: %v", listVersion)
 
 			list, err := extractList(result.list)
 			if err != nil {
@@ -165,7 +234,7 @@ mainloop:
 				break mainloop
 			}
 
-			events, err := c.cache.sync(list)
+			events, err := c.cache.sync(listVersion, list)
 			if err != nil {
 				c.log.Errorf("cache sync error: %v", err)
 				c.lc.ShutdownInitiated(err)
@@ -173,17 +242,18 @@ mainloop:
 			}
 
 			c.log.Debugf("list complete: version: %v, items: %v, events: %v",
-				version, len(list), len(events))
+				listVersion, len(list), len(events))
 
 			if !initialized {
 				c.log.Debugf("ready")
 				initialized = true
+				c.startJournal(listVersion)
 				close(c.readych)
 			} else {
-				c.distributeEvents(events)
+				c.distributeEvents(listVersion, events)
 			}
 
-			if err := c.watcher.reset(version); err != nil {
+			if err := c.watcher.reset(listVersion, resourceName(result.list)); err != nil {
 				c.log.Errorf("watcher reset error: %v", err)
 				c.lc.ShutdownInitiated(errors.Wrap(err, "watcher reset"))
 				break mainloop
@@ -198,7 +268,7 @@ mainloop:
 				c.lc.ShutdownInitiated(errors.Wrap(err, "updating cache"))
 				break mainloop
 			}
-			c.distributeEvents(events)
+			c.distributeEvents(evt.Resource().GetResourceVersion(), events)
 		}
 	}
 
@@ -207,9 +277,46 @@ mainloop:
 	<-c.lister.Done()
 }
 
-func (c *controller) distributeEvents(events []Event) {
+// warmStart() fills the cache from the snapshot read at
+// startup and resumes watching from its version.
+func (c *controller) warmStart() error {
+	if _, err := c.cache.sync(c.warm.version, c.warm.objs); err != nil {
+		return errors.Wrap(err, "cache sync")
+	}
+	return errors.Wrap(c.watcher.reset(c.warm.version, c.warm.resource), "watcher reset")
+}
+
+func (c *controller) writeSnapshot() {
+	if c.snapshot == nil {
+		return
+	}
+
+	snap, err := c.cache.Snapshot()
+	if err != nil {
+		c.log.ErrWarn(err, "snapshot: cache snapshot")
+		return
+	}
+
+	version, objs := snap.ResourceVersion(), snap.List()
+	if err := c.snapshot.write(version, objs); err != nil {
+		c.log.ErrWarn(err, "snapshot: write")
+		return
+	}
+	c.log.Debugf("snapshot: wrote %v objects at version %v", len(objs), version)
+}
+
+// startJournal() starts journaling events after version.
+func (c *controller) startJournal(version string) {
+	if err := c.journal.reset(version); err != nil {
+		c.log.ErrWarn(err, "journal reset")
+	}
+}
+
+// distributeEvents() sends events produced at version to subscribers.
+func (c *controller) distributeEvents(version string, events []Event) {
 	for _, evt := range events {
-		c.subscription.send(evt)
+		c.env.metrics.EventProcessed(evt.Type())
+		c.subscription.send(withVersion(evt, version))
 	}
 	c.log.Debugf("distribute events: %v events", len(events))
 }
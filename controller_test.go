@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/boz/kcache/client/mocks"
+	"github.com/boz/kcache/clock"
 	"github.com/boz/kcache/filter"
 	"github.com/boz/kcache/nsname"
 	"github.com/boz/kcache/testutil"
@@ -288,3 +289,75 @@ func TestController(t *testing.T) {
 	testutil.AssertDone(t, "csub_ff", csub_ff)
 
 }
+
+func TestController_clock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := clock.NewFake(time.Now())
+
+	eventch := make(chan watch.Event, 10)
+
+	mwatch := &mocks.WatchInterface{}
+	mwatch.On("ResultChan").Return(eventch)
+	mwatch.On("Stop").Return()
+
+	mretry := &mocks.WatchInterface{}
+	mretry.On("ResultChan").Return(make(chan watch.Event))
+	mretry.On("Stop").Return()
+
+	list := &v1.PodList{
+		ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+		Items:    []v1.Pod{*testGenPod("ns", "a", "1")},
+	}
+
+	listch := make(chan string, 10)
+	watchch := make(chan string, 10)
+
+	client := &mocks.Client{}
+	client.On("List", mock.Anything, mock.AnythingOfType("v1.ListOptions")).
+		Run(func(args mock.Arguments) { listch <- args.Get(1).(metav1.ListOptions).ResourceVersion }).
+		Return(list, nil)
+	client.On("Watch", mock.Anything, mock.AnythingOfType("v1.ListOptions")).
+		Run(func(args mock.Arguments) { watchch <- args.Get(1).(metav1.ListOptions).ResourceVersion }).
+		Return(mwatch, nil).Once()
+	client.On("Watch", mock.Anything, mock.AnythingOfType("v1.ListOptions")).
+		Run(func(args mock.Arguments) { watchch <- args.Get(1).(metav1.ListOptions).ResourceVersion }).
+		Return(mretry, nil)
+
+	controller, err := NewBuilder().
+		Context(ctx).
+		Client(client).
+		Clock(clk).
+		Create()
+	require.NoError(t, err)
+
+	testutil.AssertReady(t, "controller", controller)
+	testReceive(t, listch)
+	assert.Equal(t, "1", testReceive(t, watchch))
+
+	// the watch is retried once the retry delay passes.
+	eventch <- watch.Event{Type: watch.Modified, Object: testGenPod("ns", "a", "2")}
+	close(eventch)
+
+	clk.BlockUntil(2)
+	clk.Advance(watchRetryDelay)
+	assert.Equal(t, "2", testReceive(t, watchch))
+
+	// lists are refreshed every period.
+	clk.Advance(defaultRefreshPeriod)
+	testReceive(t, listch)
+
+	controller.Close()
+	testutil.AssertDone(t, "controller", controller)
+}
+
+func testReceive(t *testing.T, ch <-chan string) string {
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(time.Second):
+		require.Fail(t, "nothing received")
+		return ""
+	}
+}
@@ -0,0 +1,138 @@
+// Package debug serves the topology of kcache controllers over HTTP.
+//
+//	topology := kcache.NewTopology()
+//
+//	controller, err := kcache.NewBuilder().
+//	  Topology(topology).
+//	  Client(client).
+//	  Create()
+//
+//	http.Handle("/debug/kcache/", http.StripPrefix("/debug/kcache", debug.NewHandler(topology)))
+//
+// The handler serves:
+//
+//	/             every registered node with its parent, filter, ready state, error and buffer depth.
+//	/cache?node=N the cached objects of node N, if enabled with WithCacheContents().
+//
+// The handler is meant for operators and must not be exposed publicly:
+// filters and cached objects can reveal the contents of the cluster.
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/boz/kcache"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	redacted = "REDACTED"
+
+	// kubectl stores the applied object, including secret data, here.
+	lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// Option configures the handler.
+type Option func(*handler)
+
+// WithCacheContents() serves the cached objects of each node under
+// /cache.  The values of secrets are redacted.
+func WithCacheContents() Option {
+	return func(h *handler) {
+		h.contents = true
+	}
+}
+
+func NewHandler(topology *kcache.Topology, opts ...Option) http.Handler {
+	h := &handler{topology: topology}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", h.nodes)
+	if h.contents {
+		mux.HandleFunc("/cache", h.cache)
+	}
+	return mux
+}
+
+type handler struct {
+	topology *kcache.Topology
+	contents bool
+}
+
+func (h *handler) nodes(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, h.topology.Nodes())
+}
+
+func (h *handler) cache(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.URL.Query().Get("node"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid node id", http.StatusBadRequest)
+		return
+	}
+
+	cache, ok := h.topology.Cache(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	objs, err := cache.List()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	sort.Slice(objs, func(i, j int) bool {
+		if objs[i].GetNamespace() != objs[j].GetNamespace() {
+			return objs[i].GetNamespace() < objs[j].GetNamespace()
+		}
+		return objs[i].GetName() < objs[j].GetName()
+	})
+
+	for i, obj := range objs {
+		objs[i] = redact(obj)
+	}
+
+	writeJSON(w, objs)
+}
+
+// redact() returns a copy of obj without secret values.
+func redact(obj metav1.Object) metav1.Object {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return obj
+	}
+
+	secret = secret.DeepCopy()
+	for key := range secret.Data {
+		secret.Data[key] = []byte(redacted)
+	}
+	for key := range secret.StringData {
+		secret.StringData[key] = redacted
+	}
+	if _, ok := secret.Annotations[lastAppliedAnnotation]; ok {
+		secret.Annotations[lastAppliedAnnotation] = redacted
+	}
+	return secret
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	buf, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(buf)
+}
@@ -0,0 +1,147 @@
+package debug_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/boz/kcache"
+	"github.com/boz/kcache/client/mocks"
+	"github.com/boz/kcache/debug"
+	"github.com/boz/kcache/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mwatch := &mocks.WatchInterface{}
+	mwatch.On("ResultChan").Return(make(chan watch.Event))
+	mwatch.On("Stop").Return()
+
+	genpod := func(name string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, ResourceVersion: "1"}}
+	}
+
+	client := &mocks.Client{}
+	client.On("Watch", mock.Anything, mock.AnythingOfType("v1.ListOptions")).Return(mwatch, nil)
+	client.On("List", mock.Anything, mock.AnythingOfType("v1.ListOptions")).
+		Return(&corev1.PodList{Items: []corev1.Pod{genpod("b"), genpod("a")}}, nil)
+
+	topology := kcache.NewTopology()
+
+	controller, err := kcache.NewBuilder().
+		Context(ctx).
+		Client(client).
+		Topology(topology).
+		Create()
+	require.NoError(t, err)
+	testutil.AssertReady(t, "controller", controller)
+
+	handler := debug.NewHandler(topology, debug.WithCacheContents())
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		return rec
+	}
+
+	rec := get("/")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var nodes []kcache.TopologyNode
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &nodes))
+	require.NotEmpty(t, nodes)
+	assert.Equal(t, "controller", nodes[0].Kind)
+	assert.True(t, nodes[0].Ready)
+
+	rec = get("/cache?node=" + strconv.FormatUint(nodes[0].ID, 10))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var pods []corev1.Pod
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &pods))
+	require.Len(t, pods, 2)
+	assert.Equal(t, "a", pods[0].Name)
+	assert.Equal(t, "b", pods[1].Name)
+
+	assert.Equal(t, http.StatusBadRequest, get("/cache?node=x").Code)
+	assert.Equal(t, http.StatusNotFound, get("/cache?node=1000").Code)
+	assert.Equal(t, http.StatusNotFound, get("/other").Code)
+
+	// cache contents are only served when enabled.
+	rec = httptest.NewRecorder()
+	debug.NewHandler(topology).ServeHTTP(rec,
+		httptest.NewRequest("GET", "/cache?node="+strconv.FormatUint(nodes[0].ID, 10), nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandler_secrets(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mwatch := &mocks.WatchInterface{}
+	mwatch.On("ResultChan").Return(make(chan watch.Event))
+	mwatch.On("Stop").Return()
+
+	client := &mocks.Client{}
+	client.On("Watch", mock.Anything, mock.AnythingOfType("v1.ListOptions")).Return(mwatch, nil)
+	client.On("List", mock.Anything, mock.AnythingOfType("v1.ListOptions")).
+		Return(&corev1.SecretList{Items: []corev1.Secret{{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "ns",
+				Name:            "creds",
+				ResourceVersion: "1",
+				Annotations: map[string]string{
+					"kubectl.kubernetes.io/last-applied-configuration": `{"data":{"password":"aHVudGVyMg=="}}`,
+					"team": "a",
+				},
+			},
+			Data:       map[string][]byte{"password": []byte("hunter2")},
+			StringData: map[string]string{"token": "abc"},
+		}}}, nil)
+
+	topology := kcache.NewTopology()
+
+	controller, err := kcache.NewBuilder().
+		Context(ctx).
+		Client(client).
+		Topology(topology).
+		Create()
+	require.NoError(t, err)
+	testutil.AssertReady(t, "controller", controller)
+
+	nodes := topology.Nodes()
+	require.NotEmpty(t, nodes)
+
+	rec := httptest.NewRecorder()
+	debug.NewHandler(topology, debug.WithCacheContents()).ServeHTTP(rec,
+		httptest.NewRequest("GET", "/cache?node="+strconv.FormatUint(nodes[0].ID, 10), nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	assert.NotContains(t, rec.Body.String(), "hunter2")
+	assert.NotContains(t, rec.Body.String(), "aHVudGVyMg==")
+	assert.NotContains(t, rec.Body.String(), "abc")
+
+	var secrets []corev1.Secret
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &secrets))
+	require.Len(t, secrets, 1)
+	assert.Equal(t, []byte("REDACTED"), secrets[0].Data["password"])
+	assert.Equal(t, "REDACTED", secrets[0].StringData["token"])
+	assert.Equal(t, "a", secrets[0].Annotations["team"])
+
+	// the cached object is unchanged.
+	objs, err := controller.Cache().List()
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	assert.Equal(t, []byte("hunter2"), objs[0].(*corev1.Secret).Data["password"])
+}
@@ -0,0 +1,16 @@
+package kcache
+
+import "github.com/boz/kcache/clock"
+
+// environment holds the instrumentation, indexers and clock
+// shared by a controller and everything derived from it.
+type environment struct {
+	metrics  Metrics
+	tracer   Tracer
+	indexers Indexers
+	clock    clock.Clock
+}
+
+func defaultEnvironment() environment {
+	return environment{NullMetrics(), NullTracer(), Indexers{}, clock.Real()}
+}
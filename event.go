@@ -1,6 +1,7 @@
 package kcache
 
 import (
+	"context"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -22,10 +23,43 @@ type Event interface {
 type event struct {
 	eventType EventType
 	resource  v1.Object
+	ctx       context.Context
+
+	// version of the list or watch event which produced
+	// the event, when set by the controller.
+	version string
 }
 
 func NewEvent(et EventType, resource v1.Object) Event {
-	return event{et, resource}
+	return event{et, resource, nil, ""}
+}
+
+func newEvent(ctx context.Context, et EventType, resource v1.Object) Event {
+	return event{et, resource, ctx, ""}
+}
+
+// withVersion() returns evt as produced at version.
+func withVersion(evt Event, version string) Event {
+	return event{evt.Type(), evt.Resource(), EventContext(evt), version}
+}
+
+// eventVersion() returns the version evt was produced at, defaulting
+// to the version of its resource.
+func eventVersion(evt Event) string {
+	if evt, ok := evt.(event); ok && evt.version != "" {
+		return evt.version
+	}
+	return evt.Resource().GetResourceVersion()
+}
+
+// EventContext() returns the context evt was produced under.  When a
+// Tracer is in use it holds the span which produced evt, so that
+// handling can be traced as part of the same operation.
+func EventContext(evt Event) context.Context {
+	if evt, ok := evt.(event); ok && evt.ctx != nil {
+		return evt.ctx
+	}
+	return context.Background()
 }
 
 func (e event) Type() EventType {
@@ -0,0 +1,40 @@
+package kcache
+
+import (
+	"github.com/boz/kcache/filter"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Filtered is implemented by FilterSubscription and FilterController.
+type Filtered interface {
+	Filter() filter.Filter
+}
+
+// Rejection describes why an object was excluded by a filter.
+type Rejection struct {
+	Object      metav1.Object
+	Explanation filter.Explanation
+}
+
+// ExplainRejected() explains each object in the parent cache
+// that is rejected by the current filter of sub.
+func ExplainRejected(parent CacheReader, sub Filtered) ([]Rejection, error) {
+	list, err := parent.List()
+	if err != nil {
+		return nil, err
+	}
+
+	f := sub.Filter()
+	if f == nil {
+		return nil, nil
+	}
+
+	var result []Rejection
+	for _, obj := range list {
+		if f.Accept(obj) {
+			continue
+		}
+		result = append(result, Rejection{obj, filter.Explain(f, obj)})
+	}
+	return result, nil
+}
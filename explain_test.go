@@ -0,0 +1,46 @@
+package kcache
+
+import (
+	"testing"
+
+	logutil "github.com/boz/go-logutil"
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	"github.com/boz/kcache/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExplainRejected(t *testing.T) {
+	log := logutil.Default()
+	parent, cache, readych := testNewSubscription(t, log, filter.Null())
+	defer parent.Close()
+
+	f := filter.NSName(nsname.New("a", "1"))
+	sub := newFilterSubscription(log, defaultEnvironment(), parent, f, false)
+	assert.True(t, filter.FiltersEqual(f, sub.Filter()))
+
+	_, err := cache.sync("", []metav1.Object{testGenPod("a", "1", "1"), testGenPod("a", "2", "1")})
+	require.NoError(t, err)
+
+	close(readych)
+	testutil.AssertReady(t, "explain", sub)
+
+	rejected, err := ExplainRejected(parent.Cache(), sub)
+	require.NoError(t, err)
+	require.Len(t, rejected, 1)
+	assert.Equal(t, "2", rejected[0].Object.GetName())
+	assert.False(t, rejected[0].Explanation.Accepted)
+
+	f = filter.NSName(nsname.New("a", ""))
+	require.NoError(t, sub.Refilter(f))
+
+	// blocks until the first refilter has been applied.
+	require.NoError(t, sub.Refilter(f))
+	assert.True(t, filter.FiltersEqual(f, sub.Filter()))
+
+	rejected, err = ExplainRejected(parent.Cache(), sub)
+	require.NoError(t, err)
+	assert.Empty(t, rejected)
+}
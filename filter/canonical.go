@@ -0,0 +1,213 @@
+package filter
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+)
+
+// Canonical() returns a filter equivalent to f in a normal form:
+//
+//   - nested And() and Or() filters are flattened
+//   - children are sorted and duplicates removed
+//   - Null() and All() children are simplified away
+//   - Not(Not(x)) is reduced to x
+//
+// Two filters that differ only in these respects have
+// canonical forms that are Equals().
+func Canonical(f Filter) Filter {
+	switch f := f.(type) {
+	case andFilter:
+		return f.canonicalForm(func() Filter { return canonicalList(f.children, true) })
+	case orFilter:
+		return f.canonicalForm(func() Filter { return canonicalList(f.children, false) })
+	case *notFilter:
+		switch child := Canonical(f.child).(type) {
+		case *notFilter:
+			return child.child
+		case nullFilter:
+			return All()
+		case allFilter:
+			return Null()
+		default:
+			return Not(child)
+		}
+	case nsNameFilter:
+		if len(f.fullset) == 0 && len(f.partials) == 0 {
+			return All()
+		}
+	case *selectorFilter:
+		if f.selector.Empty() {
+			return Null()
+		}
+	}
+	return f
+}
+
+// canonicalList() returns the canonical form of an And()
+// (conjunction) or Or() of the given children.
+func canonicalList(children []Filter, conjunction bool) Filter {
+	// identity is the element that can be dropped; absorbing
+	// decides the result outright.
+	var identity, absorbing ComparableFilter = Null(), All()
+	if !conjunction {
+		identity, absorbing = absorbing, identity
+	}
+
+	var flat []Filter
+	var visit func([]Filter) bool
+
+	visit = func(children []Filter) bool {
+		for _, child := range children {
+			child = Canonical(child)
+
+			switch c := child.(type) {
+			case andFilter:
+				if conjunction {
+					if !visit(c.children) {
+						return false
+					}
+					continue
+				}
+			case orFilter:
+				if !conjunction {
+					if !visit(c.children) {
+						return false
+					}
+					continue
+				}
+			}
+
+			switch child.(type) {
+			case nullFilter, allFilter:
+				if absorbing.Equals(child) {
+					return false
+				}
+				continue
+			}
+
+			flat = append(flat, child)
+		}
+		return true
+	}
+
+	if !visit(children) {
+		return absorbing
+	}
+
+	flat = dedupeFilters(flat)
+
+	switch len(flat) {
+	case 0:
+		return identity
+	case 1:
+		return flat[0]
+	}
+
+	// the result is its own canonical form.
+	c := newComposite(flat)
+	var result Filter = orFilter{c}
+	if conjunction {
+		result = andFilter{c}
+	}
+	c.canonicalForm(func() Filter { return result })
+	return result
+}
+
+// dedupeFilters() sorts filters by their canonical key and
+// removes filters equal to an earlier one.  Filters without a key
+// are kept in their original order after the others.
+func dedupeFilters(filters []Filter) []Filter {
+	type entry struct {
+		filter Filter
+		key    string
+		ok     bool
+	}
+
+	entries := make([]entry, 0, len(filters))
+	for _, f := range filters {
+		key, ok := filterKey(f)
+		entries = append(entries, entry{f, key, ok})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].ok != entries[j].ok {
+			return entries[i].ok
+		}
+		return entries[i].ok && entries[i].key < entries[j].key
+	})
+
+	// equal filters have equal keys so only filters
+	// in the current run of keys need to be compared.
+	result := make([]Filter, 0, len(entries))
+	start := 0
+	for i, e := range entries {
+		if i > 0 && (!e.ok || e.key != entries[i-1].key) && entries[i-1].ok {
+			start = len(result)
+		}
+		if !containsFilter(result[start:], e.filter) {
+			result = append(result, e.filter)
+		}
+	}
+	return result
+}
+
+func containsFilter(filters []Filter, f Filter) bool {
+	cf, ok := f.(ComparableFilter)
+	if !ok {
+		return false
+	}
+	for _, other := range filters {
+		if cf.Equals(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// Hash() returns a hash of the canonical form of f.  Equal filters
+// have equal hashes.  ok is false if f contains a filter that
+// is not comparable or cannot be printed.
+func Hash(f Filter) (hash uint64, ok bool) {
+	key, ok := filterKey(Canonical(f))
+	if !ok {
+		return 0, false
+	}
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return h.Sum64(), true
+}
+
+// filterKey() returns the expression for f if every filter in
+// it is comparable and printable.
+func filterKey(f Filter) (string, bool) {
+	if !isKeyable(f) {
+		return "", false
+	}
+	return filterString(f), true
+}
+
+func isKeyable(f Filter) bool {
+	switch f := f.(type) {
+	case andFilter:
+		return isKeyableList(f.children)
+	case orFilter:
+		return isKeyableList(f.children)
+	case *notFilter:
+		return isKeyable(f.child)
+	}
+	if _, ok := f.(ComparableFilter); !ok {
+		return false
+	}
+	_, ok := f.(fmt.Stringer)
+	return ok
+}
+
+func isKeyableList(filters []Filter) bool {
+	for _, f := range filters {
+		if !isKeyable(f) {
+			return false
+		}
+	}
+	return true
+}
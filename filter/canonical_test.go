@@ -0,0 +1,109 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestCanonical(t *testing.T) {
+	a := filter.NSName(nsname.New("a", ""))
+	b := filter.NSName(nsname.New("b", ""))
+	c := filter.Labels(map[string]string{"c": "1"})
+
+	cases := []struct {
+		filter    filter.Filter
+		canonical filter.ComparableFilter
+	}{
+		{filter.And(), filter.Null()},
+		{filter.Or(), filter.All()},
+		{filter.And(a), a},
+		{filter.And(a, filter.Null()), a},
+		{filter.And(a, filter.All()), filter.All()},
+		{filter.Or(a, filter.All()), a},
+		{filter.Or(a, filter.Null()), filter.Null()},
+		{filter.And(b, a), filter.And(a, b)},
+		{filter.And(a, filter.And(b, c)), filter.And(a, b, c)},
+		{filter.Or(filter.Or(c, b), a), filter.Or(a, b, c)},
+		{filter.And(a, b, a), filter.And(a, b)},
+		{filter.Or(a, filter.And(b, c), filter.And(c, b)), filter.Or(a, filter.And(b, c))},
+		{filter.Not(filter.Not(a)), a},
+		{filter.Not(filter.And()), filter.All()},
+		{filter.Not(filter.Or(b, a)), filter.Not(filter.Or(a, b))},
+		{filter.NSName(), filter.All()},
+		{filter.Labels(nil), filter.Null()},
+	}
+
+	for _, c := range cases {
+		canonical := filter.Canonical(c.filter)
+		assert.True(t, c.canonical.Equals(canonical), "%v: %v", c.filter, canonical)
+	}
+}
+
+func TestCanonical_cached(t *testing.T) {
+	a := filter.NSName(nsname.New("a", ""))
+	b := filter.NSName(nsname.New("b", ""))
+	c := filter.Labels(map[string]string{"c": "1"})
+
+	f := filter.Or(b, filter.And(c, a), a)
+
+	canonical := filter.Canonical(f)
+	assert.True(t, canonical == filter.Canonical(f))
+	assert.True(t, canonical == filter.Canonical(canonical))
+	assert.True(t, filter.FiltersEqual(f, filter.Or(a, b, filter.And(a, c))))
+}
+
+func TestCanonical_uncomparable(t *testing.T) {
+	fn := filter.FN(func(_ metav1.Object) bool { return true })
+
+	assert.False(t, filter.FiltersEqual(filter.And(fn, fn), filter.And(fn)))
+
+	f := filter.Canonical(filter.And(fn, filter.Null(), filter.NSName(nsname.New("a", ""))))
+	_, ok := f.(filter.ComparableFilter)
+	assert.True(t, ok)
+
+	_, ok = filter.Hash(f)
+	assert.False(t, ok)
+}
+
+func TestFiltersEqual_order(t *testing.T) {
+	a := filter.NSName(nsname.New("a", ""), nsname.New("", "x"))
+	b := filter.Selector(labels.SelectorFromSet(labels.Set{"b": "1", "c": "2"}))
+
+	p, err := labels.Parse("c=2,b=1")
+	assert.NoError(t, err)
+
+	assert.True(t, filter.FiltersEqual(
+		filter.And(a, b),
+		filter.And(filter.Selector(p), filter.NSName(nsname.New("", "x"), nsname.New("a", ""))),
+	))
+
+	assert.True(t, filter.FiltersEqual(
+		filter.Or(a, filter.Or(b, filter.All())),
+		filter.Or(b, a),
+	))
+
+	assert.False(t, filter.FiltersEqual(filter.And(a, b), filter.Or(a, b)))
+
+	assert.True(t, filter.FiltersEqual(filter.Labels(nil), filter.LabelSelector(&metav1.LabelSelector{})))
+}
+
+func TestHash(t *testing.T) {
+	a := filter.NSName(nsname.New("a", ""))
+	b := filter.Labels(map[string]string{"b": "1"})
+
+	h1, ok := filter.Hash(filter.And(a, b))
+	assert.True(t, ok)
+
+	h2, ok := filter.Hash(filter.And(b, filter.Null(), a, a))
+	assert.True(t, ok)
+	assert.Equal(t, h1, h2)
+
+	h3, ok := filter.Hash(filter.Or(a, b))
+	assert.True(t, ok)
+	assert.NotEqual(t, h1, h3)
+}
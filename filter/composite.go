@@ -1,17 +1,41 @@
 package filter
 
 import (
+	"strings"
+	"sync"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-type andFilter []Filter
+// composite holds the children of an And() or Or() filter.  Its
+// canonical form is computed once and shared by every copy of
+// the filter, so comparing a filter with FiltersEqual() on every
+// event does not canonicalize it again.
+type composite struct {
+	children  []Filter
+	canonical Filter
+	once      sync.Once
+}
+
+func newComposite(children []Filter) *composite {
+	return &composite{children: children}
+}
+
+// canonicalForm() returns the canonical form of c, computing it
+// with fn on first use.
+func (c *composite) canonicalForm(fn func() Filter) Filter {
+	c.once.Do(func() { c.canonical = fn() })
+	return c.canonical
+}
+
+type andFilter struct{ *composite }
 
 func And(children ...Filter) ComparableFilter {
-	return andFilter(children)
+	return andFilter{newComposite(children)}
 }
 
 func (f andFilter) Accept(obj metav1.Object) bool {
-	for _, child := range f {
+	for _, child := range f.children {
 		if !child.Accept(obj) {
 			return false
 		}
@@ -21,19 +45,30 @@ func (f andFilter) Accept(obj metav1.Object) bool {
 
 func (f andFilter) Equals(other Filter) bool {
 	if other, ok := other.(andFilter); ok {
-		return compareFilterList(f, other)
+		return compareFilterList(f.children, other.children)
 	}
 	return false
 }
 
-type orFilter []Filter
+func (f andFilter) String() string {
+	if len(f.children) == 0 {
+		return "true"
+	}
+	parts := make([]string, 0, len(f.children))
+	for _, child := range f.children {
+		parts = append(parts, groupString(child))
+	}
+	return strings.Join(parts, " and ")
+}
+
+type orFilter struct{ *composite }
 
 func Or(children ...Filter) ComparableFilter {
-	return orFilter(children)
+	return orFilter{newComposite(children)}
 }
 
 func (f orFilter) Accept(obj metav1.Object) bool {
-	for _, child := range f {
+	for _, child := range f.children {
 		if child.Accept(obj) {
 			return true
 		}
@@ -43,32 +78,69 @@ func (f orFilter) Accept(obj metav1.Object) bool {
 
 func (f orFilter) Equals(other Filter) bool {
 	if other, ok := other.(orFilter); ok {
-		return compareFilterList(f, other)
+		return compareFilterList(f.children, other.children)
 	}
 	return false
 }
 
+func (f orFilter) String() string {
+	if len(f.children) == 0 {
+		return "false"
+	}
+	parts := make([]string, 0, len(f.children))
+	for _, child := range f.children {
+		if _, ok := child.(orFilter); ok {
+			parts = append(parts, groupString(child))
+			continue
+		}
+		parts = append(parts, filterString(child))
+	}
+	return strings.Join(parts, " or ")
+}
+
+// groupString() returns the expression for f, parenthesized
+// if it is a composite.
+func groupString(f Filter) string {
+	switch f.(type) {
+	case andFilter, orFilter:
+		return "(" + filterString(f) + ")"
+	default:
+		return filterString(f)
+	}
+}
+
+// compareFilterList() returns true if a and b contain
+// equal filters, in any order.
 func compareFilterList(a []Filter, b []Filter) bool {
 	if len(a) != len(b) {
 		return false
 	}
 
-	// must be in same order
-	for idx := range a {
-
+	// fast path: same order
+	idx := 0
+	for ; idx < len(a); idx++ {
 		fa, ok := a[idx].(ComparableFilter)
-		if !ok {
-			return false
+		if !ok || !fa.Equals(b[idx]) {
+			break
 		}
+	}
+
+	a, b = a[idx:], b[idx:]
+	matched := make([]bool, len(b))
 
-		fb, ok := b[idx].(ComparableFilter)
+outer:
+	for _, f := range a {
+		fa, ok := f.(ComparableFilter)
 		if !ok {
 			return false
 		}
-
-		if !fa.Equals(fb) {
-			return false
+		for j, fb := range b {
+			if !matched[j] && fa.Equals(fb) {
+				matched[j] = true
+				continue outer
+			}
 		}
+		return false
 	}
 
 	return true
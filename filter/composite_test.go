@@ -45,6 +45,11 @@ func TestAndFilter(t *testing.T) {
 
 	a = filter.And(filter.Null(), filter.All())
 	b = filter.And(filter.All(), filter.Null())
+	assert.True(t, a.Equals(b))
+	assert.True(t, b.Equals(a))
+
+	a = filter.And(filter.Null(), filter.Null())
+	b = filter.And(filter.Null(), filter.All())
 	assert.False(t, a.Equals(b))
 	assert.False(t, b.Equals(a))
 
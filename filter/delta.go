@@ -0,0 +1,89 @@
+package filter
+
+import (
+	"github.com/boz/kcache/nsname"
+)
+
+// Added() returns a filter which agrees with next on every object
+// that prev rejects.
+//
+// When both filters are unions of the same parts (Or() children or
+// NSName() ids), the result holds only the parts of next that are not
+// in prev, so objects rejected by prev can be re-evaluated against
+// just what changed.
+func Added(prev, next Filter) Filter {
+	if prev == nil || next == nil {
+		return next
+	}
+
+	prev, next = Canonical(prev), Canonical(next)
+
+	if p, ok := prev.(nsNameFilter); ok {
+		if n, ok := next.(nsNameFilter); ok {
+			return addedIDs(p, n)
+		}
+	}
+
+	pchildren, nchildren := unionParts(prev), unionParts(next)
+
+	// equal filters have equal keys so only filters
+	// with the same key need to be compared.
+	keys := make(map[string][]Filter, len(pchildren))
+	var unkeyed []Filter
+	for _, child := range pchildren {
+		if key, ok := filterKey(child); ok {
+			keys[key] = append(keys[key], child)
+		} else {
+			unkeyed = append(unkeyed, child)
+		}
+	}
+
+	var added []Filter
+	for _, child := range nchildren {
+		if key, ok := filterKey(child); ok {
+			if !containsFilter(keys[key], child) {
+				added = append(added, child)
+			}
+			continue
+		}
+		if !containsFilter(unkeyed, child) {
+			added = append(added, child)
+		}
+	}
+
+	if len(added) == len(nchildren) {
+		return next
+	}
+	return Canonical(Or(added...))
+}
+
+func unionParts(f Filter) []Filter {
+	if f, ok := f.(orFilter); ok {
+		return f.children
+	}
+	return []Filter{f}
+}
+
+func addedIDs(prev, next nsNameFilter) Filter {
+	var ids []nsname.NSName
+	for id := range next.fullset {
+		if !prev.fullset[id] {
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range next.partials {
+		if !containsID(prev.partials, id) {
+			ids = append(ids, id)
+		}
+	}
+	return NSName(ids...)
+}
+
+func containsID(ids []nsname.NSName, id nsname.NSName) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
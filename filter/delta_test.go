@@ -0,0 +1,52 @@
+package filter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdded(t *testing.T) {
+	a := filter.Labels(map[string]string{"a": "1"})
+	b := filter.Labels(map[string]string{"b": "1"})
+	c := filter.Labels(map[string]string{"c": "1"})
+
+	cases := []struct {
+		prev  filter.Filter
+		next  filter.Filter
+		added filter.Filter
+	}{
+		{nil, a, a},
+		{a, a, filter.All()},
+		{filter.Or(a, b), filter.Or(b, a, c), c},
+		{filter.Or(a, b), filter.Or(a), filter.All()},
+		{filter.Or(a, b), filter.Or(b, c), c},
+		{a, filter.Or(a, b, c), filter.Or(b, c)},
+		{a, b, b},
+		{filter.Or(a, b), filter.And(a, b), filter.And(a, b)},
+		{
+			filter.NSName(nsname.New("ns", "a"), nsname.New("ns", "b")),
+			filter.NSName(nsname.New("ns", "b"), nsname.New("ns", "c"), nsname.New("x", "")),
+			filter.NSName(nsname.New("ns", "c"), nsname.New("x", "")),
+		},
+	}
+
+	for _, c := range cases {
+		added := filter.Added(c.prev, c.next)
+		assert.True(t, filter.FiltersEqual(c.added, added), "Added(%v, %v) = %v", c.prev, c.next, added)
+	}
+}
+
+func TestAdded_subsecond(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	a := filter.CreatedAfter(base)
+	b := filter.CreatedAfter(base.Add(500 * time.Millisecond))
+	c := filter.Labels(map[string]string{"c": "1"})
+
+	added := filter.Added(filter.Or(a, c), filter.Or(b, c))
+	assert.True(t, filter.FiltersEqual(b, added), "added: %v", added)
+}
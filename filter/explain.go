@@ -0,0 +1,162 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/boz/kcache/nsname"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Explanation is the result of applying a filter to an object,
+// along with the results of any sub-filters.
+type Explanation struct {
+	// Filter is the expression of the filter that was applied.
+	Filter string `json:"filter"`
+
+	// Accepted is the result of the filter's Accept().
+	Accepted bool `json:"accepted"`
+
+	// Reason optionally describes the result.
+	Reason string `json:"reason,omitempty"`
+
+	Children []Explanation `json:"children,omitempty"`
+}
+
+// String() returns the explanation as an indented tree.
+func (e Explanation) String() string {
+	buf := &strings.Builder{}
+	e.write(buf, 0)
+	return buf.String()
+}
+
+func (e Explanation) write(buf *strings.Builder, depth int) {
+	result := "rejected"
+	if e.Accepted {
+		result = "accepted"
+	}
+
+	fmt.Fprintf(buf, "%v%v: %v", strings.Repeat("  ", depth), result, e.Filter)
+	if e.Reason != "" {
+		fmt.Fprintf(buf, " (%v)", e.Reason)
+	}
+	buf.WriteString("\n")
+
+	for _, child := range e.Children {
+		child.write(buf, depth+1)
+	}
+}
+
+// Explainer is implemented by filters that can describe
+// how they arrived at a result.
+type Explainer interface {
+	Explain(metav1.Object) Explanation
+}
+
+// Explain() returns the explanation of f's result for obj.
+// Filters that do not implement Explainer are described
+// by their Accept() result alone.
+func Explain(f Filter, obj metav1.Object) Explanation {
+	if f, ok := f.(Explainer); ok {
+		return f.Explain(obj)
+	}
+	return Explanation{
+		Filter:   filterString(f),
+		Accepted: f.Accept(obj),
+	}
+}
+
+// explainChildren() explains every child rather than
+// stopping at the first result.
+func explainChildren(children []Filter, obj metav1.Object) []Explanation {
+	result := make([]Explanation, 0, len(children))
+	for _, child := range children {
+		result = append(result, Explain(child, obj))
+	}
+	return result
+}
+
+func (f andFilter) Explain(obj metav1.Object) Explanation {
+	e := Explanation{
+		Filter:   f.String(),
+		Accepted: true,
+		Children: explainChildren(f.children, obj),
+	}
+	rejected := 0
+	for _, child := range e.Children {
+		if !child.Accepted {
+			rejected++
+		}
+	}
+	if rejected > 0 {
+		e.Accepted = false
+		e.Reason = fmt.Sprintf("%v of %v rejected", rejected, len(e.Children))
+	}
+	return e
+}
+
+func (f orFilter) Explain(obj metav1.Object) Explanation {
+	e := Explanation{
+		Filter:   f.String(),
+		Children: explainChildren(f.children, obj),
+	}
+	for _, child := range e.Children {
+		if child.Accepted {
+			e.Accepted = true
+		}
+	}
+	if !e.Accepted {
+		e.Reason = fmt.Sprintf("none of %v accepted", len(e.Children))
+	}
+	return e
+}
+
+func (f *notFilter) Explain(obj metav1.Object) Explanation {
+	child := Explain(f.child, obj)
+	return Explanation{
+		Filter:   f.String(),
+		Accepted: !child.Accepted,
+		Children: []Explanation{child},
+	}
+}
+
+func (f *selectorFilter) Explain(obj metav1.Object) Explanation {
+	set := labels.Set(obj.GetLabels())
+
+	e := Explanation{
+		Filter:   f.String(),
+		Accepted: f.selector.Matches(set),
+	}
+
+	reqs, selectable := f.selector.Requirements()
+	if !selectable {
+		e.Reason = "selector matches nothing"
+		return e
+	}
+
+	if e.Accepted {
+		return e
+	}
+
+	e.Reason = fmt.Sprintf("labels: {%v}", set)
+
+	// only unsatisfied requirements are listed.
+	for _, req := range reqs {
+		if !req.Matches(set) {
+			e.Children = append(e.Children, Explanation{Filter: req.String()})
+		}
+	}
+	return e
+}
+
+func (f nsNameFilter) Explain(obj metav1.Object) Explanation {
+	e := Explanation{
+		Filter:   f.String(),
+		Accepted: f.Accept(obj),
+	}
+	if !e.Accepted {
+		e.Reason = fmt.Sprintf("%v not matched", nsname.ForObject(obj))
+	}
+	return e
+}
@@ -0,0 +1,72 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExplain(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "a",
+		Name:      "1",
+		Labels:    map[string]string{"app": "web"},
+	}}
+
+	// the shape returned by service.PodsFilter() and friends.
+	f := filter.Or(
+		filter.And(filter.NSName(nsname.New("a", "")), filter.Labels(map[string]string{"app": "api"})),
+		filter.And(filter.NSName(nsname.New("b", "")), filter.Labels(map[string]string{"app": "web"})),
+	)
+
+	e := filter.Explain(f, pod)
+	assert.False(t, e.Accepted)
+	assert.Equal(t, f.Accept(pod), e.Accepted)
+	require.Len(t, e.Children, 2)
+
+	first := e.Children[0]
+	assert.False(t, first.Accepted)
+	require.Len(t, first.Children, 2)
+	assert.True(t, first.Children[0].Accepted)
+	assert.False(t, first.Children[1].Accepted)
+	require.Len(t, first.Children[1].Children, 1)
+	assert.Equal(t, "app=api", first.Children[1].Children[0].Filter)
+
+	second := e.Children[1]
+	assert.False(t, second.Accepted)
+	assert.False(t, second.Children[0].Accepted)
+	assert.True(t, second.Children[1].Accepted)
+
+	assert.Equal(t, `rejected: ns=a and label(app=api) or ns=b and label(app=web) (none of 2 accepted)
+  rejected: ns=a and label(app=api) (1 of 2 rejected)
+    accepted: ns=a
+    rejected: label(app=api) (labels: {app=web})
+      rejected: app=api
+  rejected: ns=b and label(app=web) (1 of 2 rejected)
+    rejected: ns=b (a/1 not matched)
+    accepted: label(app=web)
+`, e.String())
+}
+
+func TestExplain_other(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "1"}}
+
+	e := filter.Explain(filter.Not(filter.Null()), pod)
+	assert.False(t, e.Accepted)
+	require.Len(t, e.Children, 1)
+	assert.True(t, e.Children[0].Accepted)
+	assert.Equal(t, "true", e.Children[0].Filter)
+
+	fn := filter.FN(func(_ metav1.Object) bool { return true })
+	e = filter.Explain(fn, pod)
+	assert.True(t, e.Accepted)
+	assert.Empty(t, e.Children)
+
+	e = filter.Explain(filter.And(), pod)
+	assert.True(t, e.Accepted)
+}
@@ -0,0 +1,258 @@
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// FieldsFunc returns the selectable fields of an object.
+type FieldsFunc func(metav1.Object) fields.Set
+
+var fieldsRegistry = struct {
+	sync.RWMutex
+	fns map[reflect.Type]FieldsFunc
+}{fns: make(map[reflect.Type]FieldsFunc)}
+
+// RegisterFields() sets the field extractor used for objects
+// of the same type as obj.  The returned fields are merged
+// with the metadata.name and metadata.namespace fields.
+func RegisterFields(obj metav1.Object, fn FieldsFunc) {
+	fieldsRegistry.Lock()
+	defer fieldsRegistry.Unlock()
+	fieldsRegistry.fns[reflect.TypeOf(obj)] = fn
+}
+
+func init() {
+	RegisterFields(&corev1.Pod{}, podFields)
+	RegisterFields(&corev1.Event{}, eventFields)
+	RegisterFields(&corev1.Node{}, nodeFields)
+	RegisterFields(&corev1.Secret{}, secretFields)
+	RegisterFields(&corev1.Namespace{}, namespaceFields)
+	RegisterFields(&corev1.ReplicationController{}, rcFields)
+	RegisterFields(&appsv1.ReplicaSet{}, rsFields)
+	RegisterFields(&batchv1.Job{}, jobFields)
+}
+
+// ObjectFields() returns the registered selectable fields of obj.
+func ObjectFields(obj metav1.Object) fields.Set {
+	set := fields.Set{
+		"metadata.name":      obj.GetName(),
+		"metadata.namespace": obj.GetNamespace(),
+	}
+
+	fieldsRegistry.RLock()
+	fn, ok := fieldsRegistry.fns[reflect.TypeOf(obj)]
+	fieldsRegistry.RUnlock()
+
+	if ok {
+		for k, v := range fn(obj) {
+			set[k] = v
+		}
+	}
+	return set
+}
+
+// Fields() returns a filter whose Accept() returns true if the
+// object's fields match the given selector.
+//
+// Fields not provided by the object's registered extractor are
+// resolved by walking the object's structure with the dotted
+// field path.  Missing fields have an empty value.
+func Fields(selector fields.Selector) ComparableFilter {
+	if selector == nil {
+		selector = fields.Everything()
+	}
+	return &fieldsFilter{selector}
+}
+
+// FieldSelector() parses the given field selector and returns
+// a filter for it.
+func FieldSelector(selector string) (ComparableFilter, error) {
+	s, err := fields.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	return Fields(s), nil
+}
+
+type fieldsFilter struct {
+	selector fields.Selector
+}
+
+func (f *fieldsFilter) Accept(obj metav1.Object) bool {
+	if f.selector.Empty() {
+		return true
+	}
+
+	set := ObjectFields(obj)
+
+	var content map[string]interface{}
+
+	for _, req := range f.selector.Requirements() {
+		if _, ok := set[req.Field]; ok {
+			continue
+		}
+		if content == nil {
+			content = unstructuredContent(obj)
+		}
+		set[req.Field] = lookupField(content, req.Field)
+	}
+
+	return f.selector.Matches(set)
+}
+
+func (f *fieldsFilter) Equals(other Filter) bool {
+	if other, ok := other.(*fieldsFilter); ok {
+		return fieldSelectorKey(f.selector) == fieldSelectorKey(other.selector)
+	}
+	return false
+}
+
+func (f *fieldsFilter) String() string {
+	if !f.selector.Empty() && len(f.selector.Requirements()) == 0 {
+		// fields.Nothing()
+		return "false"
+	}
+	return "field(" + fieldSelectorKey(f.selector) + ")"
+}
+
+// fieldSelectorKey() returns the requirements of selector in
+// sorted order, so that reordered selectors have the same key.
+func fieldSelectorKey(selector fields.Selector) string {
+	if !selector.Empty() && len(selector.Requirements()) == 0 {
+		// fields.Nothing()
+		return "<nothing>"
+	}
+
+	reqs := selector.Requirements()
+	terms := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		terms = append(terms, req.Field+string(req.Operator)+fields.EscapeValue(req.Value))
+	}
+	sort.Strings(terms)
+	return strings.Join(terms, ",")
+}
+
+func unstructuredContent(obj metav1.Object) map[string]interface{} {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return map[string]interface{}{}
+	}
+	return content
+}
+
+func lookupField(content map[string]interface{}, path string) string {
+	var current interface{} = content
+
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		if current, ok = m[part]; !ok {
+			return ""
+		}
+	}
+
+	switch value := current.(type) {
+	case string:
+		return value
+	case bool:
+		return strconv.FormatBool(value)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case nil, map[string]interface{}, []interface{}:
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func podFields(obj metav1.Object) fields.Set {
+	pod := obj.(*corev1.Pod)
+	return fields.Set{
+		"spec.nodeName":            pod.Spec.NodeName,
+		"spec.restartPolicy":       string(pod.Spec.RestartPolicy),
+		"spec.schedulerName":       pod.Spec.SchedulerName,
+		"spec.serviceAccountName":  pod.Spec.ServiceAccountName,
+		"status.phase":             string(pod.Status.Phase),
+		"status.podIP":             pod.Status.PodIP,
+		"status.nominatedNodeName": pod.Status.NominatedNodeName,
+	}
+}
+
+func eventFields(obj metav1.Object) fields.Set {
+	event := obj.(*corev1.Event)
+	source := event.Source.Component
+	if source == "" {
+		source = event.ReportingController
+	}
+	return fields.Set{
+		"involvedObject.kind":            event.InvolvedObject.Kind,
+		"involvedObject.namespace":       event.InvolvedObject.Namespace,
+		"involvedObject.name":            event.InvolvedObject.Name,
+		"involvedObject.uid":             string(event.InvolvedObject.UID),
+		"involvedObject.apiVersion":      event.InvolvedObject.APIVersion,
+		"involvedObject.resourceVersion": event.InvolvedObject.ResourceVersion,
+		"involvedObject.fieldPath":       event.InvolvedObject.FieldPath,
+		"reason":                         event.Reason,
+		"reportingComponent":             event.ReportingController,
+		"source":                         source,
+		"type":                           event.Type,
+	}
+}
+
+func nodeFields(obj metav1.Object) fields.Set {
+	node := obj.(*corev1.Node)
+	return fields.Set{
+		"spec.unschedulable": strconv.FormatBool(node.Spec.Unschedulable),
+	}
+}
+
+func secretFields(obj metav1.Object) fields.Set {
+	secret := obj.(*corev1.Secret)
+	return fields.Set{
+		"type": string(secret.Type),
+	}
+}
+
+func namespaceFields(obj metav1.Object) fields.Set {
+	ns := obj.(*corev1.Namespace)
+	return fields.Set{
+		"status.phase": string(ns.Status.Phase),
+	}
+}
+
+func rcFields(obj metav1.Object) fields.Set {
+	rc := obj.(*corev1.ReplicationController)
+	return fields.Set{
+		"status.replicas": strconv.Itoa(int(rc.Status.Replicas)),
+	}
+}
+
+func rsFields(obj metav1.Object) fields.Set {
+	rs := obj.(*appsv1.ReplicaSet)
+	return fields.Set{
+		"status.replicas": strconv.Itoa(int(rs.Status.Replicas)),
+	}
+}
+
+func jobFields(obj metav1.Object) fields.Set {
+	job := obj.(*batchv1.Job)
+	return fields.Set{
+		"status.successful": strconv.Itoa(int(job.Status.Succeeded)),
+	}
+}
@@ -0,0 +1,122 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/boz/kcache/filter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+func TestFields(t *testing.T) {
+	genpod := func(ns, name, node string, phase v1.PodPhase) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Spec:       v1.PodSpec{NodeName: node},
+			Status:     v1.PodStatus{Phase: phase},
+		}
+	}
+
+	p1 := genpod("a", "1", "node-1", v1.PodRunning)
+	p2 := genpod("b", "2", "node-2", v1.PodPending)
+
+	f := filter.Fields(fields.OneTermEqualSelector("spec.nodeName", "node-1"))
+	assert.True(t, f.Accept(p1))
+	assert.False(t, f.Accept(p2))
+
+	f = filter.Fields(fields.OneTermNotEqualSelector("status.phase", string(v1.PodRunning)))
+	assert.False(t, f.Accept(p1))
+	assert.True(t, f.Accept(p2))
+
+	f = filter.Fields(fields.SelectorFromSet(fields.Set{
+		"metadata.namespace": "a",
+		"metadata.name":      "1",
+	}))
+	assert.True(t, f.Accept(p1))
+	assert.False(t, f.Accept(p2))
+	assert.True(t, f.Accept(&v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "1"}}))
+
+	assert.True(t, filter.Fields(nil).Accept(p1))
+	assert.True(t, filter.Fields(fields.Everything()).Accept(p1))
+	assert.False(t, filter.Fields(fields.Nothing()).Accept(p1))
+}
+
+func TestFields_event(t *testing.T) {
+	evt := &v1.Event{
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: "a", Name: "1"},
+		Reason:         "Scheduled",
+	}
+
+	f, err := filter.FieldSelector("involvedObject.kind=Pod,involvedObject.name=1")
+	require.NoError(t, err)
+	assert.True(t, f.Accept(evt))
+	assert.False(t, f.Accept(&v1.Pod{}))
+
+	f, err = filter.FieldSelector("reason!=Scheduled")
+	require.NoError(t, err)
+	assert.False(t, f.Accept(evt))
+}
+
+func TestFields_paths(t *testing.T) {
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{HostNetwork: true, Priority: new(int32)},
+	}
+
+	f, err := filter.FieldSelector("spec.hostNetwork=true")
+	require.NoError(t, err)
+	assert.True(t, f.Accept(pod))
+	assert.False(t, f.Accept(&v1.Pod{}))
+
+	f, err = filter.FieldSelector("spec.priority=0")
+	require.NoError(t, err)
+	assert.True(t, f.Accept(pod))
+
+	f, err = filter.FieldSelector("spec.missing.field=")
+	require.NoError(t, err)
+	assert.True(t, f.Accept(pod))
+
+	f, err = filter.FieldSelector("spec.missing.field=x")
+	require.NoError(t, err)
+	assert.False(t, f.Accept(pod))
+}
+
+func TestFields_register(t *testing.T) {
+	filter.RegisterFields(&v1.ConfigMap{}, func(obj metav1.Object) fields.Set {
+		return fields.Set{"data.count": "0"}
+	})
+
+	f, err := filter.FieldSelector("data.count=0")
+	require.NoError(t, err)
+	assert.True(t, f.Accept(&v1.ConfigMap{}))
+}
+
+func TestFields_equals(t *testing.T) {
+	a, err := filter.FieldSelector("spec.nodeName=a,status.phase=Running")
+	require.NoError(t, err)
+	b, err := filter.FieldSelector("status.phase=Running,spec.nodeName=a")
+	require.NoError(t, err)
+	c, err := filter.FieldSelector("spec.nodeName=a")
+	require.NoError(t, err)
+
+	assert.True(t, a.Equals(a))
+	assert.True(t, a.Equals(b))
+	assert.False(t, a.Equals(c))
+	assert.False(t, c.Equals(a))
+	assert.False(t, a.Equals(filter.Null()))
+
+	assert.True(t, filter.Fields(nil).Equals(filter.Fields(fields.Everything())))
+	assert.False(t, filter.Fields(fields.Nothing()).Equals(filter.Fields(fields.Everything())))
+
+	assert.True(t, filter.FiltersEqual(filter.And(a, c), filter.And(b, c)))
+
+	assert.Equal(t, "field(spec.nodeName=a,status.phase=Running)", b.(interface{ String() string }).String())
+
+	ha, ok := filter.Hash(a)
+	require.True(t, ok)
+	hb, ok := filter.Hash(b)
+	require.True(t, ok)
+	assert.Equal(t, ha, hb)
+}
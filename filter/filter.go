@@ -1,7 +1,7 @@
 package filter
 
 import (
-	"reflect"
+	"sort"
 
 	"github.com/boz/kcache/nsname"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +34,10 @@ func (nullFilter) Equals(other Filter) bool {
 	return ok
 }
 
+func (nullFilter) String() string {
+	return "true"
+}
+
 type allFilter struct{}
 
 // All() returns a filter whose Accept() is always false.
@@ -50,6 +54,10 @@ func (allFilter) Equals(other Filter) bool {
 	return ok
 }
 
+func (allFilter) String() string {
+	return "false"
+}
+
 func Not(child Filter) ComparableFilter {
 	return &notFilter{child}
 }
@@ -71,20 +79,35 @@ func (f *notFilter) Equals(other Filter) bool {
 	return false
 }
 
+func (f *notFilter) String() string {
+	return "not " + groupString(f.child)
+}
+
 // NSName() returns a filter whose Accept() returns true
 // if the object's namespace and name matches one of the given
 // NSNames.
 func NSName(ids ...nsname.NSName) ComparableFilter {
 	fullset := make(map[nsname.NSName]bool)
+	partialset := make(map[nsname.NSName]bool)
 	var partials []nsname.NSName
 
 	for _, id := range ids {
-		if id.Namespace != "" && id.Name != "" {
+		switch {
+		case id.Namespace != "" && id.Name != "":
 			fullset[id] = true
-		} else {
+		case !partialset[id]:
+			partialset[id] = true
 			partials = append(partials, id)
 		}
 	}
+
+	sort.Slice(partials, func(i, j int) bool {
+		if partials[i].Namespace != partials[j].Namespace {
+			return partials[i].Namespace < partials[j].Namespace
+		}
+		return partials[i].Name < partials[j].Name
+	})
+
 	return nsNameFilter{fullset, partials}
 }
 
@@ -116,9 +139,50 @@ func (f nsNameFilter) Accept(obj metav1.Object) bool {
 }
 
 func (f nsNameFilter) Equals(other Filter) bool {
-	return reflect.DeepEqual(f, other)
+	o, ok := other.(nsNameFilter)
+	if !ok || len(f.fullset) != len(o.fullset) || len(f.partials) != len(o.partials) {
+		return false
+	}
+	for id := range f.fullset {
+		if !o.fullset[id] {
+			return false
+		}
+	}
+	// partials are sorted
+	for idx, id := range f.partials {
+		if o.partials[idx] != id {
+			return false
+		}
+	}
+	return true
+}
+
+func (f nsNameFilter) String() string {
+	if len(f.fullset) == 0 && len(f.partials) == 1 {
+		switch id := f.partials[0]; {
+		case id.Namespace != "" && id.Name == "":
+			return "ns=" + quote(id.Namespace)
+		case id.Namespace == "" && id.Name != "":
+			return "name=" + quote(id.Name)
+		}
+	}
+
+	full := make([]string, 0, len(f.fullset))
+	for id := range f.fullset {
+		full = append(full, id.Namespace+"/"+id.Name)
+	}
+
+	args := sortedStrings(full)
+	for _, id := range f.partials {
+		args = append(args, id.Namespace+"/"+id.Name)
+	}
+
+	return Call("nsname", args...)
 }
 
+// FiltersEqual() returns true if the canonical forms of
+// f1 and f2 are equal.  The canonical form of an And() or
+// Or() filter is computed once and reused on later calls.
 func FiltersEqual(f1, f2 Filter) bool {
 	if f1 == nil && f2 == nil {
 		return true
@@ -128,8 +192,8 @@ func FiltersEqual(f1, f2 Filter) bool {
 		return false
 	}
 
-	if f1, ok := f1.(ComparableFilter); ok {
-		return f1.Equals(f2)
+	if f1, ok := Canonical(f1).(ComparableFilter); ok {
+		return f1.Equals(Canonical(f2))
 	}
 
 	return false
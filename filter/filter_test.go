@@ -91,7 +91,8 @@ func TestNSName_partials(t *testing.T) {
 	assert.True(t, filter.NSName(n1).Equals(filter.NSName(n1)))
 	assert.False(t, filter.NSName(n1).Equals(filter.NSName(n2)))
 	assert.True(t, filter.NSName(n1, n2).Equals(filter.NSName(n1, n2)))
-	assert.False(t, filter.NSName(n1, n2).Equals(filter.NSName(n2, n1)))
+	assert.True(t, filter.NSName(n1, n2).Equals(filter.NSName(n2, n1)))
+	assert.True(t, filter.NSName(n1, n1).Equals(filter.NSName(n1)))
 }
 
 func TestFiltersEqual(t *testing.T) {
@@ -104,6 +105,10 @@ func TestFiltersEqual(t *testing.T) {
 
 	assert.True(t, filter.FiltersEqual(filter.NSName(nsname.New("a", "1")), filter.NSName(nsname.New("a", "1"))))
 	assert.False(t, filter.FiltersEqual(filter.NSName(nsname.New("a", "1")), filter.NSName(nsname.New("a", "2"))))
+
+	assert.True(t, filter.FiltersEqual(filter.And(filter.Null()), filter.Null()))
+	assert.True(t, filter.FiltersEqual(filter.Or(), filter.All()))
+	assert.True(t, filter.FiltersEqual(filter.Not(filter.Not(filter.All())), filter.All()))
 }
 
 func TestFN(t *testing.T) {
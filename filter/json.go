@@ -0,0 +1,299 @@
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/boz/kcache/nsname"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var (
+	ErrNotSerializable = errors.New("filter not serializable")
+	ErrUnknownKind     = errors.New("unknown filter kind")
+)
+
+// Serialized filters are encoded as {"kind": <kind>, "spec": <spec>}
+// where the format of spec is determined by the kind.
+type envelope struct {
+	Kind string          `json:"kind"`
+	Spec json.RawMessage `json:"spec,omitempty"`
+}
+
+// UnmarshalFunc decodes the spec of a serialized filter.
+type UnmarshalFunc func(spec json.RawMessage) (ComparableFilter, error)
+
+var kindRegistry = struct {
+	sync.RWMutex
+	fns map[string]UnmarshalFunc
+}{fns: make(map[string]UnmarshalFunc)}
+
+// RegisterKind() makes filters of the given kind available to Unmarshal().
+//
+// Filters of a registered kind should implement json.Marshaler
+// with MarshalKind().
+func RegisterKind(kind string, fn UnmarshalFunc) {
+	kindRegistry.Lock()
+	defer kindRegistry.Unlock()
+	kindRegistry.fns[kind] = fn
+}
+
+func init() {
+	RegisterKind("null", func(_ json.RawMessage) (ComparableFilter, error) { return Null(), nil })
+	RegisterKind("all", func(_ json.RawMessage) (ComparableFilter, error) { return All(), nil })
+	RegisterKind("not", unmarshalNot)
+	RegisterKind("and", unmarshalAnd)
+	RegisterKind("or", unmarshalOr)
+	RegisterKind("nsname", unmarshalNSName)
+	RegisterKind("labels", unmarshalLabels)
+	RegisterKind("fields", unmarshalFields)
+	RegisterKind("regexp", unmarshalRegexp)
+}
+
+// MarshalKind() returns the serialized form of a filter
+// with the given kind and spec.
+func MarshalKind(kind string, spec interface{}) ([]byte, error) {
+	env := envelope{Kind: kind}
+	if spec != nil {
+		buf, err := json.Marshal(spec)
+		if err != nil {
+			return nil, err
+		}
+		env.Spec = buf
+	}
+	return json.Marshal(env)
+}
+
+// Marshal() returns the JSON encoding of f.
+func Marshal(f Filter) ([]byte, error) {
+	if _, ok := f.(json.Marshaler); !ok {
+		return nil, fmt.Errorf("%w: %T", ErrNotSerializable, f)
+	}
+	return json.Marshal(f)
+}
+
+// Unmarshal() returns the filter encoded in data.
+func Unmarshal(data []byte) (ComparableFilter, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+
+	kindRegistry.RLock()
+	fn, ok := kindRegistry.fns[env.Kind]
+	kindRegistry.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, env.Kind)
+	}
+
+	f, err := fn(env.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("filter kind %q: %w", env.Kind, err)
+	}
+	return f, nil
+}
+
+func (nullFilter) MarshalJSON() ([]byte, error) {
+	return MarshalKind("null", nil)
+}
+
+func (allFilter) MarshalJSON() ([]byte, error) {
+	return MarshalKind("all", nil)
+}
+
+func (f *notFilter) MarshalJSON() ([]byte, error) {
+	child, err := Marshal(f.child)
+	if err != nil {
+		return nil, err
+	}
+	return MarshalKind("not", json.RawMessage(child))
+}
+
+func unmarshalNot(spec json.RawMessage) (ComparableFilter, error) {
+	child, err := Unmarshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	return Not(child), nil
+}
+
+func (f andFilter) MarshalJSON() ([]byte, error) {
+	children, err := marshalFilterList(f.children)
+	if err != nil {
+		return nil, err
+	}
+	return MarshalKind("and", children)
+}
+
+func unmarshalAnd(spec json.RawMessage) (ComparableFilter, error) {
+	children, err := unmarshalFilterList(spec)
+	if err != nil {
+		return nil, err
+	}
+	return And(children...), nil
+}
+
+func (f orFilter) MarshalJSON() ([]byte, error) {
+	children, err := marshalFilterList(f.children)
+	if err != nil {
+		return nil, err
+	}
+	return MarshalKind("or", children)
+}
+
+func unmarshalOr(spec json.RawMessage) (ComparableFilter, error) {
+	children, err := unmarshalFilterList(spec)
+	if err != nil {
+		return nil, err
+	}
+	return Or(children...), nil
+}
+
+func marshalFilterList(filters []Filter) ([]json.RawMessage, error) {
+	result := make([]json.RawMessage, 0, len(filters))
+	for _, f := range filters {
+		buf, err := Marshal(f)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, buf)
+	}
+	return result, nil
+}
+
+func unmarshalFilterList(spec json.RawMessage) ([]Filter, error) {
+	var raw []json.RawMessage
+	if len(spec) > 0 {
+		if err := json.Unmarshal(spec, &raw); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]Filter, 0, len(raw))
+	for _, buf := range raw {
+		f, err := Unmarshal(buf)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, f)
+	}
+	return result, nil
+}
+
+type nsNameSpec struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+func (f nsNameFilter) MarshalJSON() ([]byte, error) {
+	full := make([]nsname.NSName, 0, len(f.fullset))
+	for id := range f.fullset {
+		full = append(full, id)
+	}
+	sort.Slice(full, func(i, j int) bool {
+		if full[i].Namespace != full[j].Namespace {
+			return full[i].Namespace < full[j].Namespace
+		}
+		return full[i].Name < full[j].Name
+	})
+
+	spec := make([]nsNameSpec, 0, len(f.fullset)+len(f.partials))
+	for _, id := range append(full, f.partials...) {
+		spec = append(spec, nsNameSpec{id.Namespace, id.Name})
+	}
+	return MarshalKind("nsname", spec)
+}
+
+func unmarshalNSName(spec json.RawMessage) (ComparableFilter, error) {
+	var ids []nsNameSpec
+	if len(spec) > 0 {
+		if err := json.Unmarshal(spec, &ids); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]nsname.NSName, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, nsname.New(id.Namespace, id.Name))
+	}
+	return NSName(result...), nil
+}
+
+type selectorSpec struct {
+	Selector string `json:"selector"`
+	Nothing  bool   `json:"nothing,omitempty"`
+}
+
+func (f *selectorFilter) MarshalJSON() ([]byte, error) {
+	spec := selectorSpec{Selector: f.selector.String()}
+	if !f.selector.Empty() && spec.Selector == "" {
+		spec.Nothing = true
+	}
+	return MarshalKind("labels", spec)
+}
+
+func unmarshalLabels(spec json.RawMessage) (ComparableFilter, error) {
+	var s selectorSpec
+	if err := json.Unmarshal(spec, &s); err != nil {
+		return nil, err
+	}
+	if s.Nothing {
+		return Selector(labels.Nothing()), nil
+	}
+	return parseLabelFunc(s.Selector)
+}
+
+func (f *fieldsFilter) MarshalJSON() ([]byte, error) {
+	spec := selectorSpec{Selector: f.selector.String()}
+	if !f.selector.Empty() && len(f.selector.Requirements()) == 0 {
+		spec.Nothing = true
+	}
+	return MarshalKind("fields", spec)
+}
+
+func unmarshalFields(spec json.RawMessage) (ComparableFilter, error) {
+	var s selectorSpec
+	if err := json.Unmarshal(spec, &s); err != nil {
+		return nil, err
+	}
+	if s.Nothing {
+		return Fields(fields.Nothing()), nil
+	}
+	return FieldSelector(s.Selector)
+}
+
+type regexpSpec struct {
+	Field string `json:"field"`
+	Expr  string `json:"expr"`
+}
+
+func (f *regexpFilter) MarshalJSON() ([]byte, error) {
+	return MarshalKind("regexp", regexpSpec{string(f.field), f.expr.String()})
+}
+
+func unmarshalRegexp(spec json.RawMessage) (ComparableFilter, error) {
+	var s regexpSpec
+	if err := json.Unmarshal(spec, &s); err != nil {
+		return nil, err
+	}
+
+	expr, err := regexp.Compile(s.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	switch regexpField(s.Field) {
+	case regexpName:
+		return NameRegexp(expr), nil
+	case regexpNamespace:
+		return NamespaceRegexp(expr), nil
+	default:
+		return nil, fmt.Errorf("invalid regexp field %q", s.Field)
+	}
+}
@@ -0,0 +1,76 @@
+package filter_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestMarshal(t *testing.T) {
+	filters := []filter.ComparableFilter{
+		filter.Null(),
+		filter.All(),
+		filter.Not(filter.Null()),
+		filter.And(),
+		filter.Or(),
+		filter.And(filter.Null(), filter.Or(filter.All(), filter.Not(filter.All()))),
+		filter.NSName(),
+		filter.NSName(nsname.New("a", "1"), nsname.New("b", "2"), nsname.New("c", ""), nsname.New("", "3")),
+		filter.Labels(nil),
+		filter.Labels(map[string]string{"a": "1", "b": "2"}),
+		filter.LabelSelector(nil),
+		filter.Selector(labels.Nothing()),
+		filter.Fields(fields.OneTermEqualSelector("spec.nodeName", "x")),
+		filter.Fields(fields.Nothing()),
+		filter.NameRegexp(regexp.MustCompile("^a")),
+		filter.NamespaceRegexp(regexp.MustCompile("^b")),
+	}
+
+	for _, f := range filters {
+		buf, err := filter.Marshal(f)
+		require.NoError(t, err, "%v", f)
+
+		decoded, err := filter.Unmarshal(buf)
+		require.NoError(t, err, string(buf))
+
+		assert.True(t, f.Equals(decoded), string(buf))
+	}
+}
+
+func TestMarshal_format(t *testing.T) {
+	buf, err := filter.Marshal(filter.And(filter.Null(), filter.NSName(nsname.New("a", ""))))
+	require.NoError(t, err)
+	assert.JSONEq(t,
+		`{"kind":"and","spec":[{"kind":"null"},{"kind":"nsname","spec":[{"namespace":"a"}]}]}`,
+		string(buf))
+}
+
+func TestMarshal_errors(t *testing.T) {
+	fn := filter.FN(func(_ metav1.Object) bool { return true })
+
+	_, err := filter.Marshal(fn)
+	assert.True(t, errors.Is(err, filter.ErrNotSerializable))
+
+	_, err = filter.Marshal(filter.And(filter.Null(), fn))
+	assert.True(t, errors.Is(err, filter.ErrNotSerializable))
+
+	_, err = filter.Unmarshal([]byte(`{"kind":"unknown"}`))
+	assert.True(t, errors.Is(err, filter.ErrUnknownKind))
+
+	_, err = filter.Unmarshal([]byte(`{"kind":"and","spec":[{"kind":"unknown"}]}`))
+	assert.True(t, errors.Is(err, filter.ErrUnknownKind))
+
+	_, err = filter.Unmarshal([]byte(`{"kind":"labels","spec":{"selector":"a in in"}}`))
+	assert.Error(t, err)
+
+	_, err = filter.Unmarshal([]byte(`[]`))
+	assert.Error(t, err)
+}
@@ -1,8 +1,6 @@
 package filter
 
 import (
-	"reflect"
-
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
@@ -37,7 +35,18 @@ func (f *selectorFilter) Accept(obj metav1.Object) bool {
 
 func (f *selectorFilter) Equals(other Filter) bool {
 	if other, ok := other.(*selectorFilter); ok {
-		return reflect.DeepEqual(f.selector, other.selector)
+		// selectors print their requirements in sorted order;
+		// Empty() distinguishes labels.Everything() from labels.Nothing().
+		return f.selector.Empty() == other.selector.Empty() &&
+			f.selector.String() == other.selector.String()
 	}
 	return false
 }
+
+func (f *selectorFilter) String() string {
+	if !f.selector.Empty() && f.selector.String() == "" {
+		// labels.Nothing()
+		return "false"
+	}
+	return "label(" + f.selector.String() + ")"
+}
@@ -0,0 +1,472 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func init() {
+	RegisterFunc("annotation", parseAnnotationsFunc)
+	RegisterFunc("uid", parseUIDFunc)
+	RegisterFunc("owner", parseOwnerFunc)
+	RegisterFunc("controller", parseControllerFunc)
+	RegisterFunc("finalizer", parseFinalizerFunc)
+	RegisterFunc("deleting", parseDeletingFunc)
+	RegisterFunc("created", parseCreatedFunc)
+
+	RegisterKind("annotations", unmarshalAnnotations)
+	RegisterKind("uid", unmarshalUID)
+	RegisterKind("owner", unmarshalOwner)
+	RegisterKind("controller", unmarshalController)
+	RegisterKind("finalizer", unmarshalFinalizer)
+	RegisterKind("deleting", func(_ json.RawMessage) (ComparableFilter, error) { return Deleting(), nil })
+	RegisterKind("created", unmarshalCreated)
+}
+
+// Annotations() returns a filter which returns true if
+// the provided map is a subset of the object's annotations.
+func Annotations(match map[string]string) ComparableFilter {
+	set := make(map[string]string, len(match))
+	for k, v := range match {
+		set[k] = v
+	}
+	return annotationsFilter(set)
+}
+
+type annotationsFilter map[string]string
+
+func (f annotationsFilter) Accept(obj metav1.Object) bool {
+	annotations := obj.GetAnnotations()
+	for k, v := range f {
+		if val, ok := annotations[k]; !ok || val != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (f annotationsFilter) Equals(other Filter) bool {
+	o, ok := other.(annotationsFilter)
+	if !ok || len(f) != len(o) {
+		return false
+	}
+	for k, v := range f {
+		if val, ok := o[k]; !ok || val != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (f annotationsFilter) String() string {
+	return Call("annotation", f.pairs()...)
+}
+
+func (f annotationsFilter) MarshalJSON() ([]byte, error) {
+	return MarshalKind("annotations", map[string]string(f))
+}
+
+func (f annotationsFilter) pairs() []string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, 2*len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k, f[k])
+	}
+	return pairs
+}
+
+func parseAnnotationsFunc(args []string) (ComparableFilter, error) {
+	if len(args)%2 != 0 {
+		return nil, fmt.Errorf("expected key, value pairs")
+	}
+	match := make(map[string]string, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		match[args[i]] = args[i+1]
+	}
+	return Annotations(match), nil
+}
+
+func unmarshalAnnotations(spec json.RawMessage) (ComparableFilter, error) {
+	var match map[string]string
+	if err := json.Unmarshal(spec, &match); err != nil {
+		return nil, err
+	}
+	return Annotations(match), nil
+}
+
+// UID() returns a filter whose Accept() returns true if
+// the object has one of the given UIDs.
+func UID(uids ...types.UID) ComparableFilter {
+	return uidFilter(newUIDSet(uids))
+}
+
+type uidFilter uidSet
+
+func (f uidFilter) Accept(obj metav1.Object) bool {
+	_, ok := f[obj.GetUID()]
+	return ok
+}
+
+func (f uidFilter) Equals(other Filter) bool {
+	o, ok := other.(uidFilter)
+	return ok && uidSet(f).equals(uidSet(o))
+}
+
+func (f uidFilter) String() string {
+	return Call("uid", uidSet(f).strings()...)
+}
+
+func (f uidFilter) MarshalJSON() ([]byte, error) {
+	return MarshalKind("uid", uidSet(f).strings())
+}
+
+func parseUIDFunc(args []string) (ComparableFilter, error) {
+	return UID(parseUIDs(args)...), nil
+}
+
+func unmarshalUID(spec json.RawMessage) (ComparableFilter, error) {
+	var uids []types.UID
+	if err := json.Unmarshal(spec, &uids); err != nil {
+		return nil, err
+	}
+	return UID(uids...), nil
+}
+
+// OwnedBy() returns a filter whose Accept() returns true if
+// one of the object's owner references has one of the given UIDs.
+func OwnedBy(uids ...types.UID) ComparableFilter {
+	return ownerFilter(newUIDSet(uids))
+}
+
+type ownerFilter uidSet
+
+func (f ownerFilter) Accept(obj metav1.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if _, ok := f[ref.UID]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (f ownerFilter) Equals(other Filter) bool {
+	o, ok := other.(ownerFilter)
+	return ok && uidSet(f).equals(uidSet(o))
+}
+
+func (f ownerFilter) String() string {
+	return Call("owner", uidSet(f).strings()...)
+}
+
+func (f ownerFilter) MarshalJSON() ([]byte, error) {
+	return MarshalKind("owner", uidSet(f).strings())
+}
+
+func parseOwnerFunc(args []string) (ComparableFilter, error) {
+	return OwnedBy(parseUIDs(args)...), nil
+}
+
+func unmarshalOwner(spec json.RawMessage) (ComparableFilter, error) {
+	var uids []types.UID
+	if err := json.Unmarshal(spec, &uids); err != nil {
+		return nil, err
+	}
+	return OwnedBy(uids...), nil
+}
+
+type uidSet map[types.UID]struct{}
+
+func newUIDSet(uids []types.UID) uidSet {
+	set := make(uidSet, len(uids))
+	for _, uid := range uids {
+		set[uid] = struct{}{}
+	}
+	return set
+}
+
+func (s uidSet) equals(other uidSet) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for uid := range s {
+		if _, ok := other[uid]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s uidSet) strings() []string {
+	uids := make([]string, 0, len(s))
+	for uid := range s {
+		uids = append(uids, string(uid))
+	}
+	return sortedStrings(uids)
+}
+
+func parseUIDs(args []string) []types.UID {
+	uids := make([]types.UID, 0, len(args))
+	for _, arg := range args {
+		uids = append(uids, types.UID(arg))
+	}
+	return uids
+}
+
+// OwnersOf() returns a filter whose Accept() returns true if
+// the object is an owner of one of the given objects.
+func OwnersOf(objs ...metav1.Object) ComparableFilter {
+	var uids []types.UID
+	for _, obj := range objs {
+		for _, ref := range obj.GetOwnerReferences() {
+			uids = append(uids, ref.UID)
+		}
+	}
+	return UID(uids...)
+}
+
+// DependentsOf() returns a filter whose Accept() returns true if
+// the object is owned by one of the given objects.
+func DependentsOf(objs ...metav1.Object) ComparableFilter {
+	uids := make([]types.UID, 0, len(objs))
+	for _, obj := range objs {
+		uids = append(uids, obj.GetUID())
+	}
+	return OwnedBy(uids...)
+}
+
+// ControlledBy() returns a filter whose Accept() returns true if
+// the object's controller reference has the given kind and name.
+func ControlledBy(kind, name string) ComparableFilter {
+	return &controllerFilter{kind, name}
+}
+
+type controllerFilter struct {
+	kind string
+	name string
+}
+
+func (f *controllerFilter) Accept(obj metav1.Object) bool {
+	ref := metav1.GetControllerOf(obj)
+	return ref != nil && ref.Kind == f.kind && ref.Name == f.name
+}
+
+func (f *controllerFilter) Equals(other Filter) bool {
+	if other, ok := other.(*controllerFilter); ok {
+		return *f == *other
+	}
+	return false
+}
+
+func (f *controllerFilter) String() string {
+	return Call("controller", f.kind, f.name)
+}
+
+type controllerSpec struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+func (f *controllerFilter) MarshalJSON() ([]byte, error) {
+	return MarshalKind("controller", controllerSpec{f.kind, f.name})
+}
+
+func parseControllerFunc(args []string) (ComparableFilter, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("expected 2 arguments (kind, name), got %v", len(args))
+	}
+	return ControlledBy(args[0], args[1]), nil
+}
+
+func unmarshalController(spec json.RawMessage) (ComparableFilter, error) {
+	var s controllerSpec
+	if err := json.Unmarshal(spec, &s); err != nil {
+		return nil, err
+	}
+	return ControlledBy(s.Kind, s.Name), nil
+}
+
+// HasFinalizer() returns a filter whose Accept() returns true if
+// the object has the given finalizer.
+func HasFinalizer(name string) ComparableFilter {
+	return finalizerFilter(name)
+}
+
+type finalizerFilter string
+
+func (f finalizerFilter) Accept(obj metav1.Object) bool {
+	for _, name := range obj.GetFinalizers() {
+		if name == string(f) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f finalizerFilter) Equals(other Filter) bool {
+	o, ok := other.(finalizerFilter)
+	return ok && f == o
+}
+
+func (f finalizerFilter) String() string {
+	return Call("finalizer", string(f))
+}
+
+func (f finalizerFilter) MarshalJSON() ([]byte, error) {
+	return MarshalKind("finalizer", string(f))
+}
+
+func parseFinalizerFunc(args []string) (ComparableFilter, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expected 1 argument (name), got %v", len(args))
+	}
+	return HasFinalizer(args[0]), nil
+}
+
+func unmarshalFinalizer(spec json.RawMessage) (ComparableFilter, error) {
+	var name string
+	if err := json.Unmarshal(spec, &name); err != nil {
+		return nil, err
+	}
+	return HasFinalizer(name), nil
+}
+
+// Deleting() returns a filter whose Accept() returns true if
+// the object has been marked for deletion.
+func Deleting() ComparableFilter {
+	return deletingFilter{}
+}
+
+type deletingFilter struct{}
+
+func (deletingFilter) Accept(obj metav1.Object) bool {
+	return obj.GetDeletionTimestamp() != nil
+}
+
+func (deletingFilter) Equals(other Filter) bool {
+	_, ok := other.(deletingFilter)
+	return ok
+}
+
+func (deletingFilter) String() string {
+	return "deleting()"
+}
+
+func (deletingFilter) MarshalJSON() ([]byte, error) {
+	return MarshalKind("deleting", nil)
+}
+
+func parseDeletingFunc(args []string) (ComparableFilter, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("expected no arguments, got %v", len(args))
+	}
+	return Deleting(), nil
+}
+
+// CreatedBetween() returns a filter whose Accept() returns true if
+// the object was created at or after after and before before.
+// A zero time leaves that end of the window open.
+func CreatedBetween(after, before time.Time) ComparableFilter {
+	return &createdFilter{after, before}
+}
+
+// CreatedAfter() returns a filter whose Accept() returns true if
+// the object was created at or after t.
+func CreatedAfter(t time.Time) ComparableFilter {
+	return CreatedBetween(t, time.Time{})
+}
+
+// CreatedBefore() returns a filter whose Accept() returns true if
+// the object was created before t.
+func CreatedBefore(t time.Time) ComparableFilter {
+	return CreatedBetween(time.Time{}, t)
+}
+
+type createdFilter struct {
+	after  time.Time
+	before time.Time
+}
+
+func (f *createdFilter) Accept(obj metav1.Object) bool {
+	created := obj.GetCreationTimestamp().Time
+	if !f.after.IsZero() && created.Before(f.after) {
+		return false
+	}
+	if !f.before.IsZero() && !created.Before(f.before) {
+		return false
+	}
+	return true
+}
+
+func (f *createdFilter) Equals(other Filter) bool {
+	if other, ok := other.(*createdFilter); ok {
+		return f.after.Equal(other.after) && f.before.Equal(other.before)
+	}
+	return false
+}
+
+func (f *createdFilter) String() string {
+	return Call("created", formatTime(f.after, time.RFC3339Nano), formatTime(f.before, time.RFC3339Nano))
+}
+
+type createdSpec struct {
+	After  string `json:"after,omitempty"`
+	Before string `json:"before,omitempty"`
+}
+
+func (f *createdFilter) MarshalJSON() ([]byte, error) {
+	return MarshalKind("created", createdSpec{
+		After:  formatTime(f.after, time.RFC3339Nano),
+		Before: formatTime(f.before, time.RFC3339Nano),
+	})
+}
+
+func parseCreatedFunc(args []string) (ComparableFilter, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("expected 2 arguments (after, before), got %v", len(args))
+	}
+	return parseCreated(args[0], args[1])
+}
+
+func unmarshalCreated(spec json.RawMessage) (ComparableFilter, error) {
+	var s createdSpec
+	if err := json.Unmarshal(spec, &s); err != nil {
+		return nil, err
+	}
+	return parseCreated(s.After, s.Before)
+}
+
+func parseCreated(after, before string) (ComparableFilter, error) {
+	a, err := parseTime(after)
+	if err != nil {
+		return nil, err
+	}
+	b, err := parseTime(before)
+	if err != nil {
+		return nil, err
+	}
+	return CreatedBetween(a, b), nil
+}
+
+func formatTime(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(layout)
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
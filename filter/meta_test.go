@@ -0,0 +1,217 @@
+package filter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boz/kcache/filter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestAnnotations(t *testing.T) {
+	genpod := func(annotations map[string]string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+
+	f := filter.Annotations(map[string]string{"a": "1", "b": "2"})
+	assert.True(t, f.Accept(genpod(map[string]string{"a": "1", "b": "2", "c": "3"})))
+	assert.False(t, f.Accept(genpod(map[string]string{"a": "1"})))
+	assert.False(t, f.Accept(genpod(map[string]string{"a": "1", "b": "3"})))
+	assert.False(t, f.Accept(genpod(nil)))
+
+	assert.True(t, filter.Annotations(nil).Accept(genpod(nil)))
+
+	assert.True(t, f.Equals(filter.Annotations(map[string]string{"b": "2", "a": "1"})))
+	assert.False(t, f.Equals(filter.Annotations(map[string]string{"a": "1"})))
+	assert.False(t, f.Equals(filter.Annotations(map[string]string{"a": "1", "b": "3"})))
+	assert.False(t, f.Equals(filter.Labels(map[string]string{"a": "1", "b": "2"})))
+}
+
+func TestOwnedBy(t *testing.T) {
+	genpod := func(uids ...types.UID) *v1.Pod {
+		pod := &v1.Pod{}
+		for _, uid := range uids {
+			pod.OwnerReferences = append(pod.OwnerReferences, metav1.OwnerReference{UID: uid})
+		}
+		return pod
+	}
+
+	f := filter.OwnedBy("a", "b")
+	assert.True(t, f.Accept(genpod("a")))
+	assert.True(t, f.Accept(genpod("c", "b")))
+	assert.False(t, f.Accept(genpod("c")))
+	assert.False(t, f.Accept(genpod()))
+
+	assert.False(t, filter.OwnedBy().Accept(genpod("a")))
+
+	assert.True(t, f.Equals(filter.OwnedBy("b", "a")))
+	assert.False(t, f.Equals(filter.OwnedBy("a")))
+	assert.False(t, f.Equals(filter.OwnedBy("a", "c")))
+}
+
+func TestUID(t *testing.T) {
+	genpod := func(uid types.UID) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{UID: uid}}
+	}
+
+	f := filter.UID("a", "b")
+	assert.True(t, f.Accept(genpod("a")))
+	assert.True(t, f.Accept(genpod("b")))
+	assert.False(t, f.Accept(genpod("c")))
+	assert.False(t, f.Accept(genpod("")))
+
+	assert.False(t, filter.UID().Accept(genpod("a")))
+
+	assert.True(t, f.Equals(filter.UID("b", "a")))
+	assert.False(t, f.Equals(filter.UID("a")))
+	assert.False(t, f.Equals(filter.OwnedBy("a", "b")))
+}
+
+func TestOwnersOf(t *testing.T) {
+	owner := &v1.ReplicationController{ObjectMeta: metav1.ObjectMeta{UID: "a"}}
+	other := &v1.ReplicationController{ObjectMeta: metav1.ObjectMeta{UID: "b"}}
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		UID:             "c",
+		OwnerReferences: []metav1.OwnerReference{{UID: "a"}},
+	}}
+
+	assert.True(t, filter.OwnersOf(pod).Accept(owner))
+	assert.False(t, filter.OwnersOf(pod).Accept(other))
+	assert.False(t, filter.OwnersOf().Accept(owner))
+
+	assert.True(t, filter.DependentsOf(owner).Accept(pod))
+	assert.False(t, filter.DependentsOf(other).Accept(pod))
+	assert.False(t, filter.DependentsOf().Accept(pod))
+}
+
+func TestControlledBy(t *testing.T) {
+	controller := true
+	genpod := func(kind, name string, isController bool) *v1.Pod {
+		ref := metav1.OwnerReference{Kind: kind, Name: name}
+		if isController {
+			ref.Controller = &controller
+		}
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{ref}}}
+	}
+
+	f := filter.ControlledBy("ReplicaSet", "rs-1")
+	assert.True(t, f.Accept(genpod("ReplicaSet", "rs-1", true)))
+	assert.False(t, f.Accept(genpod("ReplicaSet", "rs-1", false)))
+	assert.False(t, f.Accept(genpod("ReplicaSet", "rs-2", true)))
+	assert.False(t, f.Accept(genpod("Job", "rs-1", true)))
+	assert.False(t, f.Accept(&v1.Pod{}))
+
+	assert.True(t, f.Equals(filter.ControlledBy("ReplicaSet", "rs-1")))
+	assert.False(t, f.Equals(filter.ControlledBy("ReplicaSet", "rs-2")))
+	assert.False(t, f.Equals(filter.ControlledBy("Job", "rs-1")))
+}
+
+func TestHasFinalizer(t *testing.T) {
+	genpod := func(finalizers ...string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Finalizers: finalizers}}
+	}
+
+	f := filter.HasFinalizer("a")
+	assert.True(t, f.Accept(genpod("a")))
+	assert.True(t, f.Accept(genpod("b", "a")))
+	assert.False(t, f.Accept(genpod("b")))
+	assert.False(t, f.Accept(genpod()))
+
+	assert.True(t, f.Equals(filter.HasFinalizer("a")))
+	assert.False(t, f.Equals(filter.HasFinalizer("b")))
+}
+
+func TestDeleting(t *testing.T) {
+	now := metav1.Now()
+
+	f := filter.Deleting()
+	assert.True(t, f.Accept(&v1.Pod{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}}))
+	assert.False(t, f.Accept(&v1.Pod{}))
+
+	assert.True(t, f.Equals(filter.Deleting()))
+	assert.False(t, f.Equals(filter.Null()))
+}
+
+func TestCreated(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	genpod := func(created time.Time) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+	}
+
+	before := genpod(base.Add(-time.Hour))
+	start := genpod(base)
+	during := genpod(base.Add(time.Hour))
+	end := genpod(base.Add(2 * time.Hour))
+
+	f := filter.CreatedBetween(base, base.Add(2*time.Hour))
+	assert.False(t, f.Accept(before))
+	assert.True(t, f.Accept(start))
+	assert.True(t, f.Accept(during))
+	assert.False(t, f.Accept(end))
+
+	f = filter.CreatedAfter(base)
+	assert.False(t, f.Accept(before))
+	assert.True(t, f.Accept(start))
+	assert.True(t, f.Accept(end))
+
+	f = filter.CreatedBefore(base)
+	assert.True(t, f.Accept(before))
+	assert.False(t, f.Accept(start))
+
+	assert.True(t, filter.CreatedBetween(time.Time{}, time.Time{}).Accept(before))
+
+	assert.True(t, filter.CreatedAfter(base).Equals(filter.CreatedBetween(base.In(time.Local), time.Time{})))
+	assert.False(t, filter.CreatedAfter(base).Equals(filter.CreatedBefore(base)))
+	assert.False(t, filter.CreatedAfter(base).Equals(filter.CreatedAfter(base.Add(time.Second))))
+
+	sub := filter.CreatedAfter(base.Add(time.Millisecond))
+	expr := sub.(interface{ String() string }).String()
+	assert.Equal(t, `created(2020-01-01T00:00:00.001Z, "")`, expr)
+	parsed, err := filter.Parse(expr)
+	require.NoError(t, err)
+	assert.True(t, sub.Equals(parsed))
+}
+
+func TestMeta_serialize(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	filters := []filter.ComparableFilter{
+		filter.Annotations(map[string]string{"example.com/a": "x y", "b": ""}),
+		filter.UID("a", "b"),
+		filter.OwnedBy("a", "b"),
+		filter.OwnedBy(),
+		filter.ControlledBy("ReplicaSet", "rs-1"),
+		filter.HasFinalizer("kubernetes.io/pvc-protection"),
+		filter.Deleting(),
+		filter.CreatedBetween(base, base.Add(time.Hour)),
+		filter.CreatedAfter(base),
+		filter.CreatedBefore(base),
+		filter.And(filter.Deleting(), filter.Not(filter.HasFinalizer("a"))),
+	}
+
+	for _, f := range filters {
+		expr := f.(interface{ String() string }).String()
+		parsed, err := filter.Parse(expr)
+		require.NoError(t, err, expr)
+		assert.True(t, f.Equals(parsed), expr)
+
+		buf, err := filter.Marshal(f)
+		require.NoError(t, err, expr)
+		decoded, err := filter.Unmarshal(buf)
+		require.NoError(t, err, string(buf))
+		assert.True(t, f.Equals(decoded), string(buf))
+	}
+
+	assert.Equal(t, `controller(ReplicaSet, rs-1)`,
+		filter.ControlledBy("ReplicaSet", "rs-1").(interface{ String() string }).String())
+
+	for _, expr := range []string{`annotation(a)`, `controller(a)`, `finalizer()`, `deleting(a)`, `created(x, "")`} {
+		_, err := filter.Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
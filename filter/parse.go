@@ -0,0 +1,572 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/boz/kcache/nsname"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Filter expressions have the following grammar:
+//
+//   expr    = and { "or" and }
+//   and     = unary { "and" unary }
+//   unary   = "not" unary | primary
+//   primary = "(" expr ")" | "true" | "false" | compare | call
+//   compare = ( "ns" | "name" ) ( "=" | "!=" | "~" ) value
+//   call    = ident "(" [ value { "," value } ] ")"
+//   value   = ident | quoted-string
+//
+// For example:
+//
+//   ns=kube-system and label(app in (web,api)) and not name~"^tmp-"
+//
+// The built-in calls are label(<label selector>), field(<field selector>)
+// and nsname(<ns>/<name>, ...).  Additional calls can be added with
+// RegisterFunc() and RegisterRawFunc().
+
+// ParseError describes a malformed filter expression.
+type ParseError struct {
+	// Pos is the 1-based position of the offending input.
+	Pos int
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parse error at position %d: %s", e.Pos, e.Msg)
+}
+
+// FuncParser creates a filter from the arguments of a call expression.
+type FuncParser func(args []string) (ComparableFilter, error)
+
+// RawFuncParser creates a filter from the unparsed text between
+// the parentheses of a call expression.
+type RawFuncParser func(raw string) (ComparableFilter, error)
+
+var funcRegistry = struct {
+	sync.RWMutex
+	fns    map[string]FuncParser
+	rawfns map[string]RawFuncParser
+}{
+	fns:    make(map[string]FuncParser),
+	rawfns: make(map[string]RawFuncParser),
+}
+
+// RegisterFunc() makes name(args...) available in filter expressions.
+func RegisterFunc(name string, fn FuncParser) {
+	funcRegistry.Lock()
+	defer funcRegistry.Unlock()
+	funcRegistry.fns[name] = fn
+}
+
+// RegisterRawFunc() makes name(raw) available in filter expressions.
+func RegisterRawFunc(name string, fn RawFuncParser) {
+	funcRegistry.Lock()
+	defer funcRegistry.Unlock()
+	funcRegistry.rawfns[name] = fn
+}
+
+func init() {
+	RegisterRawFunc("label", parseLabelFunc)
+	RegisterRawFunc("field", parseFieldFunc)
+	RegisterFunc("nsname", parseNSNameFunc)
+}
+
+// Parse() returns the filter described by the given expression.
+func Parse(expr string) (ComparableFilter, error) {
+	p := &parser{lex: &lexer{input: expr}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf(p.tok.pos, "unexpected %v", p.tok)
+	}
+	return f, nil
+}
+
+// Call() formats a call expression with the given name and arguments,
+// quoting arguments as necessary.
+func Call(name string, args ...string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, quote(arg))
+	}
+	return name + "(" + strings.Join(quoted, ", ") + ")"
+}
+
+func parseLabelFunc(raw string) (ComparableFilter, error) {
+	if raw == "" {
+		return Selector(labels.Everything()), nil
+	}
+	selector, err := labels.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	return Selector(selector), nil
+}
+
+func parseFieldFunc(raw string) (ComparableFilter, error) {
+	selector, err := fields.ParseSelector(raw)
+	if err != nil {
+		return nil, err
+	}
+	return Fields(selector), nil
+}
+
+func parseNSNameFunc(args []string) (ComparableFilter, error) {
+	ids := make([]nsname.NSName, 0, len(args))
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "/", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid nsname %q: expected <ns>/<name>", arg)
+		}
+		ids = append(ids, nsname.New(parts[0], parts[1]))
+	}
+	return NSName(ids...), nil
+}
+
+// filterString() returns the expression for f.
+func filterString(f Filter) string {
+	if f, ok := f.(fmt.Stringer); ok {
+		return f.String()
+	}
+	return fmt.Sprintf("<%T>", f)
+}
+
+// quote() returns s as a bare identifier if possible,
+// otherwise as a quoted string.
+func quote(s string) string {
+	if s == "" || isKeyword(s) {
+		return strconv.Quote(s)
+	}
+	for _, r := range s {
+		if !isIdentRune(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+func sortedStrings(vals []string) []string {
+	sort.Strings(vals)
+	return vals
+}
+
+func isKeyword(s string) bool {
+	switch s {
+	case "and", "or", "not", "true", "false":
+		return true
+	}
+	return false
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_./:", r)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokLParen
+	tokRParen
+	tokComma
+	tokEq
+	tokNotEq
+	tokMatch
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of input"
+	case tokString:
+		return strconv.Quote(t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+type lexer struct {
+	input string
+	pos   int
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.input) {
+		r, size := utf8.DecodeRuneInString(l.input[l.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		l.pos += size
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+
+	start := l.pos
+
+	if l.pos >= len(l.input) {
+		return token{tokEOF, "", start}, nil
+	}
+
+	r, size := utf8.DecodeRuneInString(l.input[l.pos:])
+
+	switch {
+	case r == '(':
+		l.pos += size
+		return token{tokLParen, "(", start}, nil
+	case r == ')':
+		l.pos += size
+		return token{tokRParen, ")", start}, nil
+	case r == ',':
+		l.pos += size
+		return token{tokComma, ",", start}, nil
+	case r == '=':
+		l.pos += size
+		return token{tokEq, "=", start}, nil
+	case r == '~':
+		l.pos += size
+		return token{tokMatch, "~", start}, nil
+	case r == '!':
+		if strings.HasPrefix(l.input[l.pos:], "!=") {
+			l.pos += 2
+			return token{tokNotEq, "!=", start}, nil
+		}
+		return token{}, &ParseError{start + 1, "unexpected '!'"}
+	case r == '"':
+		return l.scanString()
+	case isIdentRune(r):
+		for l.pos < len(l.input) {
+			r, size := utf8.DecodeRuneInString(l.input[l.pos:])
+			if !isIdentRune(r) {
+				break
+			}
+			l.pos += size
+		}
+		return token{tokIdent, l.input[start:l.pos], start}, nil
+	default:
+		return token{}, &ParseError{start + 1, fmt.Sprintf("unexpected %q", r)}
+	}
+}
+
+func (l *lexer) scanString() (token, error) {
+	start := l.pos
+	l.pos++
+
+	for l.pos < len(l.input) {
+		switch l.input[l.pos] {
+		case '\\':
+			l.pos += 2
+			continue
+		case '"':
+			l.pos++
+			text, err := strconv.Unquote(l.input[start:l.pos])
+			if err != nil {
+				return token{}, &ParseError{start + 1, "invalid quoted string"}
+			}
+			return token{tokString, text, start}, nil
+		}
+		l.pos++
+	}
+
+	return token{}, &ParseError{start + 1, "unterminated quoted string"}
+}
+
+// raw() consumes and returns the input up to the parenthesis
+// that closes the one at open.
+func (l *lexer) raw(open int) (string, error) {
+	start := l.pos
+	depth := 0
+
+	for l.pos < len(l.input) {
+		switch l.input[l.pos] {
+		case '"':
+			if _, err := l.scanString(); err != nil {
+				return "", err
+			}
+			continue
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				text := l.input[start:l.pos]
+				l.pos++
+				return strings.TrimSpace(text), nil
+			}
+			depth--
+		}
+		l.pos++
+	}
+
+	return "", &ParseError{open + 1, "unterminated '('"}
+}
+
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) errorf(pos int, format string, args ...interface{}) error {
+	return &ParseError{pos + 1, fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) isKeyword(word string) bool {
+	return p.tok.kind == tokIdent && p.tok.text == word
+}
+
+func (p *parser) expect(kind tokenKind, desc string) (token, error) {
+	tok := p.tok
+	if tok.kind != kind {
+		return tok, p.errorf(tok.pos, "expected %v, found %v", desc, tok)
+	}
+	return tok, p.advance()
+}
+
+func (p *parser) parseOr() (ComparableFilter, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []Filter{first}
+	for p.isKeyword("or") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		child, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+	return Or(children...), nil
+}
+
+func (p *parser) parseAnd() (ComparableFilter, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []Filter{first}
+	for p.isKeyword("and") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+	return And(children...), nil
+}
+
+func (p *parser) parseUnary() (ComparableFilter, error) {
+	if !p.isKeyword("not") {
+		return p.parsePrimary()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	child, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return Not(child), nil
+}
+
+func (p *parser) parsePrimary() (ComparableFilter, error) {
+	tok := p.tok
+
+	switch tok.kind {
+	case tokLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return f, nil
+	case tokIdent:
+	default:
+		return nil, p.errorf(tok.pos, "expected filter, found %v", tok)
+	}
+
+	if isKeyword(tok.text) {
+		switch tok.text {
+		case "true":
+			return Null(), p.advance()
+		case "false":
+			return All(), p.advance()
+		default:
+			return nil, p.errorf(tok.pos, "expected filter, found %v", tok)
+		}
+	}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	switch p.tok.kind {
+	case tokEq, tokNotEq, tokMatch:
+		return p.parseCompare(tok)
+	case tokLParen:
+		return p.parseCall(tok)
+	default:
+		return nil, p.errorf(p.tok.pos, "expected operator or '(' after %v, found %v", tok, p.tok)
+	}
+}
+
+func (p *parser) parseCompare(field token) (ComparableFilter, error) {
+	op := p.tok
+
+	if field.text != "ns" && field.text != "name" {
+		return nil, p.errorf(field.pos, "unknown field %v: expected \"ns\" or \"name\"", field)
+	}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if op.kind == tokMatch {
+		expr, err := regexp.Compile(value.text)
+		if err != nil {
+			return nil, p.errorf(value.pos, "invalid regular expression: %v", err)
+		}
+		if field.text == "ns" {
+			return NamespaceRegexp(expr), nil
+		}
+		return NameRegexp(expr), nil
+	}
+
+	var f ComparableFilter
+	if field.text == "ns" {
+		f = NSName(nsname.New(value.text, ""))
+	} else {
+		f = NSName(nsname.New("", value.text))
+	}
+
+	if op.kind == tokNotEq {
+		return Not(f), nil
+	}
+	return f, nil
+}
+
+func (p *parser) parseValue() (token, error) {
+	tok := p.tok
+	if tok.kind != tokIdent && tok.kind != tokString {
+		return tok, p.errorf(tok.pos, "expected value, found %v", tok)
+	}
+	return tok, p.advance()
+}
+
+func (p *parser) parseCall(name token) (ComparableFilter, error) {
+	open := p.tok
+
+	funcRegistry.RLock()
+	rawfn, israw := funcRegistry.rawfns[name.text]
+	fn, isfn := funcRegistry.fns[name.text]
+	funcRegistry.RUnlock()
+
+	switch {
+	case israw:
+		raw, err := p.lex.raw(open.pos)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		f, err := rawfn(raw)
+		if err != nil {
+			return nil, p.errorf(name.pos, "%v: %v", name.text, err)
+		}
+		return f, nil
+	case !isfn:
+		return nil, p.errorf(name.pos, "unknown function %v", name)
+	}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var args []string
+
+	if p.tok.kind != tokRParen {
+		for {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, value.text)
+
+			if p.tok.kind != tokComma {
+				break
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if _, err := p.expect(tokRParen, "',' or ')'"); err != nil {
+		return nil, err
+	}
+
+	f, err := fn(args)
+	if err != nil {
+		return nil, p.errorf(name.pos, "%v: %v", name.text, err)
+	}
+	return f, nil
+}
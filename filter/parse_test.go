@@ -0,0 +1,143 @@
+package filter_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+func TestParse(t *testing.T) {
+	genpod := func(ns, name string, labels map[string]string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, Labels: labels}}
+	}
+
+	f, err := filter.Parse(`ns=kube-system and label(app in (web,api)) and not name~"^tmp-"`)
+	require.NoError(t, err)
+
+	assert.True(t, f.Accept(genpod("kube-system", "web-1", map[string]string{"app": "web"})))
+	assert.True(t, f.Accept(genpod("kube-system", "api-1", map[string]string{"app": "api"})))
+	assert.False(t, f.Accept(genpod("kube-system", "tmp-1", map[string]string{"app": "api"})))
+	assert.False(t, f.Accept(genpod("default", "web-1", map[string]string{"app": "web"})))
+	assert.False(t, f.Accept(genpod("kube-system", "db-1", map[string]string{"app": "db"})))
+
+	f, err = filter.Parse(`ns=a or ns=b and name!=x`)
+	require.NoError(t, err)
+	assert.True(t, f.Accept(genpod("a", "x", nil)))
+	assert.True(t, f.Accept(genpod("b", "y", nil)))
+	assert.False(t, f.Accept(genpod("b", "x", nil)))
+
+	f, err = filter.Parse(`(ns=a or ns=b) and name!=x`)
+	require.NoError(t, err)
+	assert.False(t, f.Accept(genpod("a", "x", nil)))
+	assert.True(t, f.Accept(genpod("a", "y", nil)))
+
+	f, err = filter.Parse(`field(spec.nodeName=node-1)`)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(filter.Fields(fields.OneTermEqualSelector("spec.nodeName", "node-1"))))
+
+	f, err = filter.Parse(`nsname(a/1, b/, /2)`)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(filter.NSName(nsname.New("a", "1"), nsname.New("b", ""), nsname.New("", "2"))))
+
+	f, err = filter.Parse(`true`)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(filter.Null()))
+
+	f, err = filter.Parse(`not false`)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(filter.Not(filter.All())))
+}
+
+func TestParse_roundtrip(t *testing.T) {
+	exprs := []string{
+		`true`,
+		`false`,
+		`ns=a`,
+		`name=b`,
+		`not ns=a`,
+		`ns="a b"`,
+		`name~"^tmp-"`,
+		`ns~"kube-.*"`,
+		`label(app in (api,web))`,
+		`label()`,
+		`field(spec.nodeName=x)`,
+		`nsname(a/1, b/2)`,
+		`nsname(a/1, b/)`,
+		`ns=a and name=b`,
+		`ns=a or name=b`,
+		`ns=a and name=b or ns=c`,
+		`ns=a and (name=b or ns=c)`,
+		`(ns=a and name=b) and ns=c`,
+		`(ns=a or name=b) or ns=c`,
+		`not (ns=a or ns=b)`,
+		`not not ns=a`,
+	}
+
+	for _, expr := range exprs {
+		f, err := filter.Parse(expr)
+		require.NoError(t, err, expr)
+		assert.Equal(t, expr, f.(interface{ String() string }).String(), expr)
+
+		f2, err := filter.Parse(expr)
+		require.NoError(t, err, expr)
+		assert.True(t, f.Equals(f2), expr)
+	}
+
+	filters := []filter.ComparableFilter{
+		filter.Null(),
+		filter.All(),
+		filter.Labels(map[string]string{"a": "1", "b": "2"}),
+		filter.NSName(nsname.New("a", "1"), nsname.New("b", "2"), nsname.New("c", "")),
+		filter.NameRegexp(regexp.MustCompile(`a"b`)),
+		filter.And(filter.Not(filter.Null()), filter.Or(filter.All(), filter.Null())),
+	}
+
+	for _, f := range filters {
+		expr := f.(interface{ String() string }).String()
+		parsed, err := filter.Parse(expr)
+		require.NoError(t, err, expr)
+		assert.True(t, f.Equals(parsed), expr)
+	}
+}
+
+func TestParse_errors(t *testing.T) {
+	cases := []struct {
+		expr string
+		pos  int
+	}{
+		{``, 1},
+		{`ns=`, 4},
+		{`ns=a and`, 9},
+		{`ns=a or or`, 9},
+		{`(ns=a`, 6},
+		{`ns=a)`, 5},
+		{`foo=a`, 1},
+		{`foo(a)`, 1},
+		{`label(a in (b)`, 6},
+		{`label(a in in)`, 1},
+		{`name~"("`, 6},
+		{`ns="a`, 4},
+		{`ns=a ! name=b`, 6},
+		{`ns=a & name=b`, 6},
+		{`nsname(a)`, 1},
+		{`nsname(a/b c/d)`, 12},
+		{`ns`, 3},
+	}
+
+	for _, c := range cases {
+		_, err := filter.Parse(c.expr)
+		if assert.Error(t, err, c.expr) {
+			perr, ok := err.(*filter.ParseError)
+			if assert.True(t, ok, c.expr) {
+				assert.Equal(t, c.pos, perr.Pos, "%v: %v", c.expr, err)
+			}
+		}
+	}
+}
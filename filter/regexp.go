@@ -0,0 +1,51 @@
+package filter
+
+import (
+	"regexp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NameRegexp() returns a filter whose Accept() returns true
+// if the object's name matches the given expression.
+func NameRegexp(expr *regexp.Regexp) ComparableFilter {
+	return &regexpFilter{regexpName, expr}
+}
+
+// NamespaceRegexp() returns a filter whose Accept() returns true
+// if the object's namespace matches the given expression.
+func NamespaceRegexp(expr *regexp.Regexp) ComparableFilter {
+	return &regexpFilter{regexpNamespace, expr}
+}
+
+type regexpField string
+
+const (
+	regexpName      regexpField = "name"
+	regexpNamespace regexpField = "ns"
+)
+
+type regexpFilter struct {
+	field regexpField
+	expr  *regexp.Regexp
+}
+
+func (f *regexpFilter) Accept(obj metav1.Object) bool {
+	switch f.field {
+	case regexpName:
+		return f.expr.MatchString(obj.GetName())
+	default:
+		return f.expr.MatchString(obj.GetNamespace())
+	}
+}
+
+func (f *regexpFilter) Equals(other Filter) bool {
+	if other, ok := other.(*regexpFilter); ok {
+		return f.field == other.field && f.expr.String() == other.expr.String()
+	}
+	return false
+}
+
+func (f *regexpFilter) String() string {
+	return string(f.field) + "~" + quote(f.expr.String())
+}
@@ -0,0 +1,312 @@
+package join
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	logutil "github.com/boz/go-logutil"
+	"github.com/boz/kcache"
+	"github.com/boz/kcache/filter"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Refilterable is implemented by the FilterController of every typed package.
+type Refilterable interface {
+	Closer
+	Refilter(filter.Filter) error
+	CloseWithError(error)
+}
+
+// Closer is implemented by every typed controller.
+type Closer interface {
+	Close()
+	Done() <-chan struct{}
+}
+
+// Publisher is implemented by every typed publisher whose
+// CloneForFilter() returns a D.
+type Publisher[D Refilterable] interface {
+	CloneForFilter() (D, error)
+}
+
+// Source is implemented by every typed controller.  S is the
+// type of its objects, e.g. *corev1.Service for service.Controller.
+type Source[S any] interface {
+	Untyped
+}
+
+// Selection derives a destination filter from the current
+// objects of a source controller.
+type Selection struct {
+	src kcache.Controller
+	fn  func([]metav1.Object) filter.ComparableFilter
+}
+
+// Select() returns a selection which applies fn to the objects
+// of src that are of type S.
+func Select[S any](src Untyped, fn func([]S) filter.ComparableFilter) Selection {
+	return Selection{
+		src: src.Untyped(),
+		fn: func(objs []metav1.Object) filter.ComparableFilter {
+			typed := make([]S, 0, len(objs))
+			for _, obj := range objs {
+				if obj, ok := obj.(S); ok {
+					typed = append(typed, obj)
+				}
+			}
+			return fn(typed)
+		},
+	}
+}
+
+func (s Selection) current() (filter.ComparableFilter, error) {
+	objs, err := s.src.Cache().List()
+	if err != nil {
+		return nil, err
+	}
+	return s.fn(objs), nil
+}
+
+// Variadic() adapts a typed filter function such as
+// service.PodsFilter for use with By() and Select().
+func Variadic[S any](fn func(...S) filter.ComparableFilter) func([]S) filter.ComparableFilter {
+	return func(objs []S) filter.ComparableFilter {
+		return fn(objs...)
+	}
+}
+
+// By() restricts a clone of dst to the objects selected by
+// applying fn to the objects of src.
+//
+// The result is closed when src is done, with src's error if it
+// failed.  The join follows a clone of src, so closing the result
+// leaves src running.
+//
+//	pods, err := join.By[*corev1.Service, pod.FilterController](ctx, svcs, pods, join.Variadic(service.PodsFilter))
+func By[S any, D Refilterable](ctx context.Context,
+	src Source[S], dst Publisher[D], fn func([]S) filter.ComparableFilter, opts ...Option) (D, error) {
+	return Union(ctx, dst, []Selection{Select(src, fn)}, opts...)
+}
+
+// Union() restricts a clone of dst to the objects
+// accepted by any of the given selections.  As with By(), the
+// result is closed when any source is done, and closing it leaves
+// the sources running.
+func Union[D Refilterable](ctx context.Context, dst Publisher[D], sels []Selection, opts ...Option) (D, error) {
+	return combine(ctx, dst, filter.Or, sels, newOptions(opts))
+}
+
+// Intersection() restricts a clone of dst to the objects
+// accepted by all of the given selections.
+func Intersection[D Refilterable](ctx context.Context, dst Publisher[D], sels []Selection, opts ...Option) (D, error) {
+	return combine(ctx, dst, filter.And, sels, newOptions(opts))
+}
+
+// Chain() joins through an intermediate controller: next is given
+// the result of first, which is closed once the result of next is done.
+// Both are given opts.
+//
+//	pods, err := join.Chain(ctx,
+//	  func(ctx context.Context, opts ...join.Option) (service.FilterController, error) {
+//	    return join.By[*networkingv1.Ingress, service.FilterController](ctx, ings, svcs, join.Variadic(ingress.ServicesFilter), opts...)
+//	  },
+//	  func(ctx context.Context, svcs service.FilterController, opts ...join.Option) (pod.FilterController, error) {
+//	    return join.By[*corev1.Service, pod.FilterController](ctx, svcs, pods, join.Variadic(service.PodsFilter), opts...)
+//	  })
+func Chain[M Closer, D Closer](ctx context.Context,
+	first func(context.Context, ...Option) (M, error),
+	next func(context.Context, M, ...Option) (D, error), opts ...Option) (D, error) {
+
+	mid, err := first(ctx, opts...)
+	if err != nil {
+		var zero D
+		return zero, err
+	}
+
+	dst, err := next(ctx, mid, opts...)
+	if err != nil {
+		mid.Close()
+		var zero D
+		return zero, err
+	}
+
+	go func() {
+		<-dst.Done()
+		mid.Close()
+	}()
+
+	return dst, nil
+}
+
+func combine[D Refilterable](ctx context.Context,
+	dstController Publisher[D], op func(...filter.Filter) filter.ComparableFilter, sels []Selection, opts options) (D, error) {
+
+	log := logutil.FromContextOrDefault(ctx)
+	observe := opts.observer
+
+	var zero D
+
+	dst, err := dstController.CloneForFilter()
+	if err != nil {
+		return zero, err
+	}
+
+	// follow clones of the sources so that closing the join
+	// leaves the caller's controllers running.
+	srcs := make([]kcache.Controller, 0, len(sels))
+	clones := make([]Selection, 0, len(sels))
+	for _, sel := range sels {
+		src, err := sel.src.Clone()
+		if err != nil {
+			for _, clone := range clones {
+				clone.src.Close()
+			}
+			dst.Close()
+			return zero, log.Err(err, "join: clone source")
+		}
+		srcs = append(srcs, sel.src)
+		clones = append(clones, Selection{src, sel.fn})
+	}
+	sels = clones
+
+	var (
+		mtx     sync.Mutex
+		pending = len(sels)
+		window  = opts.debounce
+		timer   *time.Timer
+		current filter.Filter
+	)
+
+	// refilter() must be called with mtx held.
+	refilter := func() {
+		filters := make([]filter.Filter, 0, len(sels))
+		for _, sel := range sels {
+			f, err := sel.current()
+			if err != nil {
+				err = errors.Wrap(err, "join: cache list")
+				observe(Refiltered{Err: err})
+				dst.CloseWithError(err)
+				return
+			}
+			filters = append(filters, f)
+		}
+
+		f := op(filters...)
+		if current != nil && filter.FiltersEqual(current, f) {
+			return
+		}
+		current = f
+
+		err := dst.Refilter(f)
+		if err != nil {
+			log.ErrWarn(err, "join: refilter")
+		}
+		observe(Refiltered{f, err})
+	}
+
+	update := func(_ metav1.Object) {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		switch {
+		case pending > 0:
+		case window == 0:
+			refilter()
+		case timer == nil:
+			timer = time.AfterFunc(window, func() {
+				mtx.Lock()
+				defer mtx.Unlock()
+				if timer != nil {
+					timer = nil
+					refilter()
+				}
+			})
+		}
+	}
+
+	handler := kcache.BuildHandler().
+		OnInitialize(func(_ []metav1.Object) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			if pending--; pending == 0 {
+				refilter()
+			}
+		}).
+		OnCreate(update).
+		OnUpdate(update).
+		OnDelete(update).
+		Create()
+
+	if len(sels) == 0 {
+		mtx.Lock()
+		refilter()
+		mtx.Unlock()
+	}
+
+	monitors := make([]kcache.Monitor, 0, len(sels))
+	for _, sel := range sels {
+		monitor, err := kcache.NewMonitor(sel.src, handler)
+		if err != nil {
+			for _, monitor := range monitors {
+				monitor.Close()
+			}
+			for _, sel := range sels {
+				sel.src.Close()
+			}
+			dst.Close()
+			return zero, log.Err(err, "join: monitor")
+		}
+		monitors = append(monitors, monitor)
+	}
+
+	// close dst when a source or its monitor is done.
+	for i, src := range srcs {
+		go func(src kcache.Controller, monitor kcache.Monitor) {
+			select {
+			case <-dst.Done():
+				return
+			case <-monitor.Done():
+			}
+
+			err := monitor.Error()
+			if err == nil {
+				// the monitor closes before the source its clone follows is done.
+				select {
+				case <-dst.Done():
+					return
+				case <-src.Done():
+					err = src.Error()
+				}
+			}
+
+			if err != nil {
+				dst.CloseWithError(errors.Wrap(err, "join: source"))
+				return
+			}
+			dst.Close()
+		}(src, monitors[i])
+	}
+
+	// close the monitors and source clones when dst is done.
+	go func() {
+		<-dst.Done()
+
+		mtx.Lock()
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+		}
+		mtx.Unlock()
+
+		for _, monitor := range monitors {
+			monitor.Close()
+		}
+		for _, sel := range sels {
+			sel.src.Close()
+		}
+	}()
+
+	return dst, nil
+}
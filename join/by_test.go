@@ -0,0 +1,251 @@
+package join_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	logutil "github.com/boz/go-logutil"
+	"github.com/boz/kcache/client/mocks"
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/join"
+	"github.com/boz/kcache/nsname"
+	"github.com/boz/kcache/testutil"
+	"github.com/boz/kcache/types/pod"
+	"github.com/boz/kcache/types/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestBy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	svcs, pods := testControllers(t, ctx)
+
+	svcs1, err := svcs.CloneWithFilter(filter.NSName(nsname.New("ns", "s1")))
+	require.NoError(t, err)
+
+	joined, err := join.By[*corev1.Service, pod.FilterController](ctx, svcs1, pods, join.Variadic(service.PodsFilter))
+	require.NoError(t, err)
+
+	testutil.AssertReady(t, "joined", joined)
+	assert.Equal(t, []string{"a", "b"}, podNames(t, joined))
+
+	joined.Close()
+	testutil.AssertDone(t, "joined", joined)
+}
+
+func TestIntersection(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	svcs, pods := testControllers(t, ctx)
+
+	svcs1, err := svcs.CloneWithFilter(filter.NSName(nsname.New("ns", "s1")))
+	require.NoError(t, err)
+	svcs2, err := svcs.CloneWithFilter(filter.NSName(nsname.New("ns", "s2")))
+	require.NoError(t, err)
+
+	fn := join.Variadic(service.PodsFilter)
+
+	both, err := join.Intersection[pod.FilterController](ctx, pods,
+		[]join.Selection{join.Select(svcs1, fn), join.Select(svcs2, fn)})
+	require.NoError(t, err)
+
+	either, err := join.Union[pod.FilterController](ctx, pods,
+		[]join.Selection{join.Select(svcs1, fn), join.Select(svcs2, fn)})
+	require.NoError(t, err)
+
+	testutil.AssertReady(t, "both", both)
+	testutil.AssertReady(t, "either", either)
+
+	assert.Equal(t, []string{"b"}, podNames(t, both))
+	assert.Equal(t, []string{"a", "b", "c"}, podNames(t, either))
+}
+
+func TestChain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	svcs, pods := testControllers(t, ctx)
+
+	var mid pod.FilterController
+
+	joined, err := join.Chain(ctx,
+		func(ctx context.Context, _ ...join.Option) (pod.FilterController, error) {
+			c, err := pods.CloneWithFilter(filter.NSName(nsname.New("ns", "a")))
+			mid = c
+			return c, err
+		},
+		func(ctx context.Context, src pod.FilterController, opts ...join.Option) (service.FilterController, error) {
+			return join.By[*corev1.Pod, service.FilterController](ctx, src, svcs, join.Variadic(join.PodServicesFilter), opts...)
+		})
+	require.NoError(t, err)
+
+	testutil.AssertReady(t, "joined", joined)
+
+	objs, err := joined.Cache().List()
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	assert.Equal(t, "s1", objs[0].Name)
+
+	joined.Close()
+	testutil.AssertDone(t, "mid", mid)
+}
+
+func TestBy_debounce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, pods := testControllers(t, ctx)
+
+	gensvc := func(name, vsn string, selector map[string]string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, ResourceVersion: vsn},
+			Spec:       corev1.ServiceSpec{Selector: selector},
+		}
+	}
+
+	eventch := make(chan watch.Event, 10)
+	svcs, err := service.BuildController(ctx, logutil.Default(), testWatchClient(&corev1.ServiceList{
+		Items: []corev1.Service{*gensvc("s1", "1", map[string]string{"app": "x"})},
+	}, eventch))
+	require.NoError(t, err)
+
+	joined, err := join.By[*corev1.Service, pod.FilterController](ctx, svcs, pods, join.Variadic(service.PodsFilter),
+		join.Debounce(200*time.Millisecond))
+	require.NoError(t, err)
+
+	testutil.AssertReady(t, "joined", joined)
+	assert.Equal(t, []string{"a", "b"}, podNames(t, joined))
+
+	eventch <- watch.Event{Type: watch.Modified, Object: gensvc("s1", "2", map[string]string{"tier": "none"})}
+	eventch <- watch.Event{Type: watch.Added, Object: gensvc("s2", "3", map[string]string{"tier": "web"})}
+
+	// updates are coalesced until the window has passed.
+	assert.Equal(t, []string{"a", "b"}, podNames(t, joined))
+
+	assert.Eventually(t, func() bool {
+		names := podNames(t, joined)
+		return len(names) == 2 && names[0] == "b" && names[1] == "c"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestBy_lifecycle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		mtx      sync.Mutex
+		observed []join.Refiltered
+	)
+	observer := join.Observer(func(r join.Refiltered) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		observed = append(observed, r)
+	})
+
+	svcs, pods := testControllers(t, ctx)
+
+	src, err := svcs.CloneWithFilter(filter.NSName(nsname.New("ns", "s1")))
+	require.NoError(t, err)
+
+	joined, err := join.By[*corev1.Service, pod.FilterController](ctx, src, pods, join.Variadic(service.PodsFilter), observer)
+	require.NoError(t, err)
+
+	testutil.AssertReady(t, "joined", joined)
+
+	assert.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(observed) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	mtx.Lock()
+	assert.NoError(t, observed[0].Err)
+	assert.True(t, filter.FiltersEqual(observed[0].Filter, joined.Filter()))
+	mtx.Unlock()
+
+	// source failure closes the join with the source's error.
+	src.CloseWithError(errors.New("boom"))
+	testutil.AssertDone(t, "joined", joined)
+	require.Error(t, joined.Error())
+	assert.Contains(t, joined.Error().Error(), "boom")
+
+	// closing the join leaves its source running.
+	joined, err = join.By[*corev1.Service, pod.FilterController](ctx, svcs, pods, join.Variadic(service.PodsFilter))
+	require.NoError(t, err)
+	testutil.AssertReady(t, "joined", joined)
+
+	joined.Close()
+	testutil.AssertDone(t, "joined", joined)
+	testutil.AssertNotDone(t, "svcs", svcs)
+	testutil.AssertNotDone(t, "pods", pods)
+}
+
+func testControllers(t *testing.T, ctx context.Context) (service.Controller, pod.Controller) {
+	genpod := func(name string, labels map[string]string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: labels, ResourceVersion: "1"}}
+	}
+	gensvc := func(name string, selector map[string]string) corev1.Service {
+		return corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, ResourceVersion: "1"},
+			Spec:       corev1.ServiceSpec{Selector: selector},
+		}
+	}
+
+	svcs, err := service.BuildController(ctx, logutil.Default(), testClient(&corev1.ServiceList{
+		Items: []corev1.Service{
+			gensvc("s1", map[string]string{"app": "x"}),
+			gensvc("s2", map[string]string{"tier": "web"}),
+		},
+	}))
+	require.NoError(t, err)
+
+	pods, err := pod.BuildController(ctx, logutil.Default(), testClient(&corev1.PodList{
+		Items: []corev1.Pod{
+			genpod("a", map[string]string{"app": "x"}),
+			genpod("b", map[string]string{"app": "x", "tier": "web"}),
+			genpod("c", map[string]string{"tier": "web"}),
+		},
+	}))
+	require.NoError(t, err)
+
+	return svcs, pods
+}
+
+func testClient(list runtime.Object) *mocks.Client {
+	return testWatchClient(list, make(chan watch.Event))
+}
+
+func testWatchClient(list runtime.Object, eventch chan watch.Event) *mocks.Client {
+	mwatch := &mocks.WatchInterface{}
+	mwatch.On("ResultChan").Return(eventch)
+	mwatch.On("Stop").Return()
+
+	client := &mocks.Client{}
+	client.On("Watch", mock.Anything, mock.AnythingOfType("v1.ListOptions")).Return(mwatch, nil)
+	client.On("List", mock.Anything, mock.AnythingOfType("v1.ListOptions")).Return(list, nil)
+	return client
+}
+
+func podNames(t *testing.T, c pod.FilterController) []string {
+	objs, err := c.Cache().List()
+	require.NoError(t, err)
+
+	var names []string
+	for _, obj := range objs {
+		names = append(names, obj.Name)
+	}
+	sort.Strings(names)
+	return names
+}
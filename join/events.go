@@ -0,0 +1,30 @@
+package join
+
+import (
+	"context"
+
+	"github.com/boz/kcache"
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/types/event"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Untyped is implemented by all typed controllers.
+type Untyped interface {
+	Untyped() kcache.Controller
+}
+
+// ObjectEvents() joins the objects of any typed controller to
+// the events whose involved object is one of them.
+func ObjectEvents(ctx context.Context, src Untyped, dst event.Publisher, opts ...Option) (event.Controller, error) {
+	return ObjectEventsWith(ctx, src.Untyped(), dst, opts...)
+}
+
+// ObjectEventsWith() joins the objects of the given controller to
+// the events whose involved object is one of them.
+func ObjectEventsWith(ctx context.Context, srcController kcache.Controller, dstController event.Publisher, opts ...Option) (event.Controller, error) {
+	sel := Selection{srcController, func(objs []metav1.Object) filter.ComparableFilter {
+		return event.InvolvedObjectsFilter(objs...)
+	}}
+	return Union[event.FilterController](ctx, dstController, []Selection{sel}, opts...)
+}
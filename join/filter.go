@@ -0,0 +1,70 @@
+package join
+
+import (
+	"sort"
+
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	"github.com/boz/kcache/types/deployment"
+	"github.com/boz/kcache/types/ingress"
+	"github.com/boz/kcache/types/pod"
+	"github.com/boz/kcache/types/service"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodServicesFilter() returns a filter which accepts the
+// services whose selectors match the given pods.
+func PodServicesFilter(sources ...*corev1.Pod) filter.ComparableFilter {
+	return selectedBy(service.SelectorMatchFilter, sources)
+}
+
+// PodDeploymentsFilter() returns a filter which accepts the
+// deployments whose selectors match the given pods.
+func PodDeploymentsFilter(sources ...*corev1.Pod) filter.ComparableFilter {
+	return selectedBy(deployment.SelectorMatchFilter, sources)
+}
+
+func selectedBy(fn func(map[string]string) filter.ComparableFilter, sources []*corev1.Pod) filter.ComparableFilter {
+
+	// make a copy and sort
+	srcs := make([]*corev1.Pod, len(sources))
+	copy(srcs, sources)
+
+	sort.Slice(srcs, func(i, j int) bool {
+		if srcs[i].Namespace != srcs[j].Namespace {
+			return srcs[i].Namespace < srcs[j].Namespace
+		}
+		return srcs[i].Name < srcs[j].Name
+	})
+
+	var filters []filter.Filter
+
+	for _, pod := range srcs {
+		if len(pod.Labels) > 0 {
+			nsfilter := filter.NSName(nsname.New(pod.GetNamespace(), ""))
+			filters = append(filters, filter.And(nsfilter, fn(pod.Labels)))
+		}
+	}
+
+	return filter.Or(filters...)
+}
+
+// ServiceIngressesFilter() returns a filter which accepts the ingresses
+// that use one of the given services as a backend.
+func ServiceIngressesFilter(services ...*corev1.Service) filter.ComparableFilter {
+	ids := make([]nsname.NSName, 0, len(services))
+	for _, svc := range services {
+		ids = append(ids, nsname.ForObject(svc))
+	}
+	return ingress.BackendsFilter(ids...)
+}
+
+// NodePodsFilter() returns a filter which accepts pods
+// scheduled on the given nodes.
+func NodePodsFilter(sources ...*corev1.Node) filter.ComparableFilter {
+	names := make([]string, 0, len(sources))
+	for _, node := range sources {
+		names = append(names, node.GetName())
+	}
+	return pod.NodeFilter(names...)
+}
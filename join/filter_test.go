@@ -0,0 +1,85 @@
+package join_test
+
+import (
+	"testing"
+
+	"github.com/boz/kcache/join"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodServicesFilter(t *testing.T) {
+	genpod := func(ns string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "p", Labels: labels}}
+	}
+	gensvc := func(ns string, selector map[string]string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns},
+			Spec:       corev1.ServiceSpec{Selector: selector},
+		}
+	}
+
+	p := genpod("a", map[string]string{"app": "x", "tier": "web"})
+
+	assert.True(t, join.PodServicesFilter(p).Accept(gensvc("a", map[string]string{"app": "x"})))
+	assert.False(t, join.PodServicesFilter(p).Accept(gensvc("a", map[string]string{"app": "y"})))
+	assert.False(t, join.PodServicesFilter(p).Accept(gensvc("b", map[string]string{"app": "x"})))
+	assert.False(t, join.PodServicesFilter(genpod("a", nil)).Accept(gensvc("a", map[string]string{"app": "x"})))
+
+	assert.True(t, join.PodServicesFilter(p).Equals(join.PodServicesFilter(p)))
+}
+
+func TestPodDeploymentsFilter(t *testing.T) {
+	p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Labels: map[string]string{"app": "x"}}}
+
+	gendep := func(ns string, selector map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: selector}},
+		}
+	}
+
+	assert.True(t, join.PodDeploymentsFilter(p).Accept(gendep("a", map[string]string{"app": "x"})))
+	assert.False(t, join.PodDeploymentsFilter(p).Accept(gendep("a", map[string]string{"app": "y"})))
+	assert.False(t, join.PodDeploymentsFilter(p).Accept(gendep("b", map[string]string{"app": "x"})))
+}
+
+func TestServiceIngressesFilter(t *testing.T) {
+	gening := func(ns, svc string) *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "ing"},
+			Spec: networkingv1.IngressSpec{
+				DefaultBackend: &networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{Name: svc},
+				},
+			},
+		}
+	}
+
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "foo"}}
+
+	assert.True(t, join.ServiceIngressesFilter(svc).Accept(gening("a", "foo")))
+	assert.False(t, join.ServiceIngressesFilter(svc).Accept(gening("a", "bar")))
+	assert.False(t, join.ServiceIngressesFilter(svc).Accept(gening("b", "foo")))
+	assert.False(t, join.ServiceIngressesFilter().Accept(gening("a", "foo")))
+}
+
+func TestNodePodsFilter(t *testing.T) {
+	genpod := func(node string) *corev1.Pod {
+		return &corev1.Pod{Spec: corev1.PodSpec{NodeName: node}}
+	}
+	gennode := func(name string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	assert.True(t, join.NodePodsFilter(gennode("a")).Accept(genpod("a")))
+	assert.True(t, join.NodePodsFilter(gennode("a"), gennode("b")).Accept(genpod("b")))
+	assert.False(t, join.NodePodsFilter(gennode("a")).Accept(genpod("b")))
+	assert.False(t, join.NodePodsFilter(gennode("a")).Accept(genpod("")))
+	assert.False(t, join.NodePodsFilter().Accept(genpod("a")))
+
+	assert.True(t, join.NodePodsFilter(gennode("a"), gennode("b")).Equals(join.NodePodsFilter(gennode("b"), gennode("a"))))
+}
@@ -2,9 +2,11 @@ package join
 
 import (
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
-	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -16,8 +18,17 @@ var _ corev1.Event
 var _ corev1.Node
 var _ corev1.ReplicationController
 var _ appsv1.Deployment
-var _ networkingv1beta1.Ingress
+var _ networkingv1.Ingress
 var _ appsv1.ReplicaSet
 var _ appsv1.DaemonSet
 var _ batchv1.Job
 var _ appsv1.StatefulSet
+var _ corev1.ConfigMap
+var _ corev1.Namespace
+var _ corev1.PersistentVolume
+var _ corev1.PersistentVolumeClaim
+var _ corev1.Endpoints
+var _ discoveryv1.EndpointSlice
+var _ batchv1.CronJob
+var _ autoscalingv1.HorizontalPodAutoscaler
+var _ corev1.ServiceAccount
@@ -3,67 +3,206 @@ package join
 import (
 	"context"
 
+	"github.com/boz/kcache/types/cronjob"
 	"github.com/boz/kcache/types/daemonset"
 	"github.com/boz/kcache/types/deployment"
 	"github.com/boz/kcache/types/ingress"
 	"github.com/boz/kcache/types/job"
+	"github.com/boz/kcache/types/node"
 	"github.com/boz/kcache/types/pod"
 	"github.com/boz/kcache/types/replicaset"
 	"github.com/boz/kcache/types/replicationcontroller"
 	"github.com/boz/kcache/types/service"
 	"github.com/boz/kcache/types/statefulset"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 )
 
 func ServicePods(ctx context.Context,
-	src service.Controller, dst pod.Publisher) (pod.Controller, error) {
-	return ServicePodsWith(ctx, src, dst, service.PodsFilter)
+	src service.Controller, dst pod.Publisher, opts ...Option) (pod.Controller, error) {
+	return By[*corev1.Service, pod.FilterController](ctx, src, dst, Variadic(service.PodsFilter), opts...)
 }
 
 func RCPods(ctx context.Context,
-	src replicationcontroller.Controller, dst pod.Publisher) (pod.Controller, error) {
-	return RCPodsWith(ctx, src, dst, replicationcontroller.PodsFilter)
+	src replicationcontroller.Controller, dst pod.Publisher, opts ...Option) (pod.Controller, error) {
+	return By[*corev1.ReplicationController, pod.FilterController](ctx, src, dst, Variadic(replicationcontroller.PodsFilter), opts...)
 }
 
 func RSPods(ctx context.Context,
-	src replicaset.Controller, dst pod.Publisher) (pod.Controller, error) {
-	return RSPodsWith(ctx, src, dst, replicaset.PodsFilter)
+	src replicaset.Controller, dst pod.Publisher, opts ...Option) (pod.Controller, error) {
+	return By[*appsv1.ReplicaSet, pod.FilterController](ctx, src, dst, Variadic(replicaset.PodsFilter), opts...)
 }
 
 func DeploymentPods(ctx context.Context,
-	src deployment.Controller, dst pod.Publisher) (pod.Controller, error) {
-	return DeploymentPodsWith(ctx, src, dst, deployment.PodsFilter)
+	src deployment.Controller, dst pod.Publisher, opts ...Option) (pod.Controller, error) {
+	return By[*appsv1.Deployment, pod.FilterController](ctx, src, dst, Variadic(deployment.PodsFilter), opts...)
 }
 
 func StatefulSetPods(ctx context.Context,
-	src statefulset.Controller, dst pod.Publisher) (pod.Controller, error) {
-	return StatefulSetPodsWith(ctx, src, dst, statefulset.PodsFilter)
+	src statefulset.Controller, dst pod.Publisher, opts ...Option) (pod.Controller, error) {
+	return By[*appsv1.StatefulSet, pod.FilterController](ctx, src, dst, Variadic(statefulset.PodsFilter), opts...)
 }
 
 func JobPods(ctx context.Context,
-	src job.Controller, dst pod.Publisher) (pod.Controller, error) {
-	return JobPodsWith(ctx, src, dst, job.PodsFilter)
+	src job.Controller, dst pod.Publisher, opts ...Option) (pod.Controller, error) {
+	return By[*batchv1.Job, pod.FilterController](ctx, src, dst, Variadic(job.PodsFilter), opts...)
 }
 
 func DaemonSetPods(ctx context.Context,
-	src daemonset.Controller, dst pod.Publisher) (pod.Controller, error) {
-	return DaemonSetPodsWith(ctx, src, dst, daemonset.PodsFilter)
+	src daemonset.Controller, dst pod.Publisher, opts ...Option) (pod.Controller, error) {
+	return By[*appsv1.DaemonSet, pod.FilterController](ctx, src, dst, Variadic(daemonset.PodsFilter), opts...)
 }
 
 func IngressServices(ctx context.Context,
-	src ingress.Controller, dst service.Publisher) (service.Controller, error) {
-	return IngressServicesWith(ctx, src, dst, ingress.ServicesFilter)
-}
-
-func IngressPods(ctx context.Context, srcbase ingress.Controller, svcbase service.Controller, dstbase pod.Controller) (pod.Controller, error) {
-	svcs, err := IngressServices(ctx, srcbase, svcbase)
-	if err != nil {
-		return nil, err
-	}
-
-	pods, err := ServicePods(ctx, svcs, dstbase)
-	if err != nil {
-		svcs.Close()
-		return nil, err
-	}
-	return pods, nil
+	src ingress.Controller, dst service.Publisher, opts ...Option) (service.Controller, error) {
+	return By[*networkingv1.Ingress, service.FilterController](ctx, src, dst, Variadic(ingress.ServicesFilter), opts...)
+}
+
+func IngressPods(ctx context.Context, srcbase ingress.Controller, svcbase service.Controller, dstbase pod.Controller, opts ...Option) (pod.Controller, error) {
+	return Chain(ctx,
+		func(ctx context.Context, opts ...Option) (service.Controller, error) {
+			return IngressServices(ctx, srcbase, svcbase, opts...)
+		},
+		func(ctx context.Context, svcs service.Controller, opts ...Option) (pod.Controller, error) {
+			return ServicePods(ctx, svcs, dstbase, opts...)
+		}, opts...)
+}
+
+// DeploymentReplicaSets() joins deployments to the replica sets
+// they own, by owner reference.
+func DeploymentReplicaSets(ctx context.Context,
+	src deployment.Controller, dst replicaset.Publisher, opts ...Option) (replicaset.Controller, error) {
+	return By[*appsv1.Deployment, replicaset.FilterController](ctx, src, dst, Variadic(deployment.ReplicaSetsFilter), opts...)
+}
+
+// RSOwnedPods() joins replica sets to the pods they own, by owner reference.
+func RSOwnedPods(ctx context.Context,
+	src replicaset.Controller, dst pod.Publisher, opts ...Option) (pod.Controller, error) {
+	return By[*appsv1.ReplicaSet, pod.FilterController](ctx, src, dst, Variadic(replicaset.OwnedPodsFilter), opts...)
+}
+
+// DeploymentOwnedPods() joins deployments to the pods owned by
+// their replica sets.
+func DeploymentOwnedPods(ctx context.Context, srcbase deployment.Controller, rsbase replicaset.Controller, dstbase pod.Controller, opts ...Option) (pod.Controller, error) {
+	return Chain(ctx,
+		func(ctx context.Context, opts ...Option) (replicaset.Controller, error) {
+			return DeploymentReplicaSets(ctx, srcbase, rsbase, opts...)
+		},
+		func(ctx context.Context, rss replicaset.Controller, opts ...Option) (pod.Controller, error) {
+			return RSOwnedPods(ctx, rss, dstbase, opts...)
+		}, opts...)
+}
+
+// CronJobJobs() joins cron jobs to the jobs they own, by owner reference.
+func CronJobJobs(ctx context.Context,
+	src cronjob.Controller, dst job.Publisher, opts ...Option) (job.Controller, error) {
+	return By[*batchv1.CronJob, job.FilterController](ctx, src, dst, Variadic(cronjob.OwnedJobsFilter), opts...)
+}
+
+// JobOwnedPods() joins jobs to the pods they own, by owner reference.
+func JobOwnedPods(ctx context.Context,
+	src job.Controller, dst pod.Publisher, opts ...Option) (pod.Controller, error) {
+	return By[*batchv1.Job, pod.FilterController](ctx, src, dst, Variadic(job.OwnedPodsFilter), opts...)
+}
+
+// CronJobOwnedPods() joins cron jobs to the pods owned by their jobs.
+func CronJobOwnedPods(ctx context.Context, srcbase cronjob.Controller, jobbase job.Controller, dstbase pod.Controller, opts ...Option) (pod.Controller, error) {
+	return Chain(ctx,
+		func(ctx context.Context, opts ...Option) (job.Controller, error) {
+			return CronJobJobs(ctx, srcbase, jobbase, opts...)
+		},
+		func(ctx context.Context, jobs job.Controller, opts ...Option) (pod.Controller, error) {
+			return JobOwnedPods(ctx, jobs, dstbase, opts...)
+		}, opts...)
+}
+
+// PodOwnerReplicaSets() joins pods to the replica sets that own them.
+func PodOwnerReplicaSets(ctx context.Context,
+	src pod.Controller, dst replicaset.Publisher, opts ...Option) (replicaset.Controller, error) {
+	return By[*corev1.Pod, replicaset.FilterController](ctx, src, dst, Variadic(pod.OwnersFilter), opts...)
+}
+
+// RSOwnerDeployments() joins replica sets to the deployments that own them.
+func RSOwnerDeployments(ctx context.Context,
+	src replicaset.Controller, dst deployment.Publisher, opts ...Option) (deployment.Controller, error) {
+	return By[*appsv1.ReplicaSet, deployment.FilterController](ctx, src, dst, Variadic(replicaset.OwnersFilter), opts...)
+}
+
+// PodOwnerDeployments() joins pods to the deployments that own
+// their replica sets.
+func PodOwnerDeployments(ctx context.Context, srcbase pod.Controller, rsbase replicaset.Controller, dstbase deployment.Controller, opts ...Option) (deployment.Controller, error) {
+	return Chain(ctx,
+		func(ctx context.Context, opts ...Option) (replicaset.Controller, error) {
+			return PodOwnerReplicaSets(ctx, srcbase, rsbase, opts...)
+		},
+		func(ctx context.Context, rss replicaset.Controller, opts ...Option) (deployment.Controller, error) {
+			return RSOwnerDeployments(ctx, rss, dstbase, opts...)
+		}, opts...)
+}
+
+// PodOwnerJobs() joins pods to the jobs that own them.
+func PodOwnerJobs(ctx context.Context,
+	src pod.Controller, dst job.Publisher, opts ...Option) (job.Controller, error) {
+	return By[*corev1.Pod, job.FilterController](ctx, src, dst, Variadic(pod.OwnersFilter), opts...)
+}
+
+// JobOwnerCronJobs() joins jobs to the cron jobs that own them.
+func JobOwnerCronJobs(ctx context.Context,
+	src job.Controller, dst cronjob.Publisher, opts ...Option) (cronjob.Controller, error) {
+	return By[*batchv1.Job, cronjob.FilterController](ctx, src, dst, Variadic(job.OwnersFilter), opts...)
+}
+
+// PodOwnerCronJobs() joins pods to the cron jobs that own their jobs.
+func PodOwnerCronJobs(ctx context.Context, srcbase pod.Controller, jobbase job.Controller, dstbase cronjob.Controller, opts ...Option) (cronjob.Controller, error) {
+	return Chain(ctx,
+		func(ctx context.Context, opts ...Option) (job.Controller, error) {
+			return PodOwnerJobs(ctx, srcbase, jobbase, opts...)
+		},
+		func(ctx context.Context, jobs job.Controller, opts ...Option) (cronjob.Controller, error) {
+			return JobOwnerCronJobs(ctx, jobs, dstbase, opts...)
+		}, opts...)
+}
+
+// PodServices() joins pods to the services whose selectors match them.
+func PodServices(ctx context.Context,
+	src pod.Controller, dst service.Publisher, opts ...Option) (service.Controller, error) {
+	return By[*corev1.Pod, service.FilterController](ctx, src, dst, Variadic(PodServicesFilter), opts...)
+}
+
+// PodDeployments() joins pods to the deployments whose selectors match them.
+func PodDeployments(ctx context.Context,
+	src pod.Controller, dst deployment.Publisher, opts ...Option) (deployment.Controller, error) {
+	return By[*corev1.Pod, deployment.FilterController](ctx, src, dst, Variadic(PodDeploymentsFilter), opts...)
+}
+
+// ServiceIngresses() joins services to the ingresses that use them as backends.
+func ServiceIngresses(ctx context.Context,
+	src service.Controller, dst ingress.Publisher, opts ...Option) (ingress.Controller, error) {
+	return By[*corev1.Service, ingress.FilterController](ctx, src, dst, Variadic(ServiceIngressesFilter), opts...)
+}
+
+// PodIngresses() joins pods to the ingresses that route to
+// the services whose selectors match them.
+func PodIngresses(ctx context.Context, srcbase pod.Controller, svcbase service.Controller, dstbase ingress.Controller, opts ...Option) (ingress.Controller, error) {
+	return Chain(ctx,
+		func(ctx context.Context, opts ...Option) (service.Controller, error) {
+			return PodServices(ctx, srcbase, svcbase, opts...)
+		},
+		func(ctx context.Context, svcs service.Controller, opts ...Option) (ingress.Controller, error) {
+			return ServiceIngresses(ctx, svcs, dstbase, opts...)
+		}, opts...)
+}
+
+// NodePods() joins nodes to the pods scheduled on them.
+func NodePods(ctx context.Context,
+	src node.Controller, dst pod.Publisher, opts ...Option) (pod.Controller, error) {
+	return By[*corev1.Node, pod.FilterController](ctx, src, dst, Variadic(NodePodsFilter), opts...)
+}
+
+// PodNodes() joins pods to the nodes they are scheduled on.
+func PodNodes(ctx context.Context,
+	src pod.Controller, dst node.Publisher, opts ...Option) (node.Controller, error) {
+	return By[*corev1.Pod, node.FilterController](ctx, src, dst, Variadic(pod.NodesFilter), opts...)
 }
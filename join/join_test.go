@@ -0,0 +1,89 @@
+package join_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	logutil "github.com/boz/go-logutil"
+	"github.com/boz/kcache/join"
+	"github.com/boz/kcache/testutil"
+	"github.com/boz/kcache/types/cronjob"
+	"github.com/boz/kcache/types/job"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestCronJobJobs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	controller := true
+	genjob := func(name, owner, uid string) batchv1.Job {
+		return batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "ns",
+			Name:            name,
+			ResourceVersion: "1",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "CronJob", Name: owner, UID: types.UID(uid), Controller: &controller},
+			},
+		}}
+	}
+
+	// "backup" was deleted and recreated; job "backup-1" belonged
+	// to the deleted cron job.
+	cronjobs, err := cronjob.BuildController(ctx, logutil.Default(), testClient(&batchv1.CronJobList{
+		Items: []batchv1.CronJob{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "backup", UID: "uid-2", ResourceVersion: "1"},
+		}},
+	}))
+	require.NoError(t, err)
+
+	jobs, err := job.BuildController(ctx, logutil.Default(), testClient(&batchv1.JobList{
+		Items: []batchv1.Job{
+			genjob("backup-1", "backup", "uid-1"),
+			genjob("backup-2", "backup", "uid-2"),
+		},
+	}))
+	require.NoError(t, err)
+
+	joined, err := join.CronJobJobs(ctx, cronjobs, jobs)
+	require.NoError(t, err)
+	testutil.AssertReady(t, "joined", joined)
+
+	assert.Eventually(t, func() bool {
+		list, err := joined.Cache().List()
+		require.NoError(t, err)
+		var names []string
+		for _, obj := range list {
+			names = append(names, obj.Name)
+		}
+		sort.Strings(names)
+		return assert.ObjectsAreEqual([]string{"backup-2"}, names)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestServicePods_close(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	svcs, pods := testControllers(t, ctx)
+
+	joined, err := join.ServicePods(ctx, svcs, pods)
+	require.NoError(t, err)
+	testutil.AssertReady(t, "joined", joined)
+
+	joined.Close()
+	testutil.AssertDone(t, "joined", joined)
+	testutil.AssertNotDone(t, "svcs", svcs)
+	testutil.AssertNotDone(t, "pods", pods)
+
+	// the services can still be joined.
+	joined, err = join.ServicePods(ctx, svcs, pods)
+	require.NoError(t, err)
+	testutil.AssertReady(t, "joined", joined)
+}
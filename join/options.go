@@ -0,0 +1,54 @@
+package join
+
+import (
+	"time"
+
+	"github.com/boz/kcache/filter"
+)
+
+// DefaultDebounce is the window over which joins coalesce
+// source updates unless set with Debounce().
+const DefaultDebounce = 100 * time.Millisecond
+
+// Option configures a join.
+type Option func(*options)
+
+type options struct {
+	debounce time.Duration
+	observer func(Refiltered)
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		debounce: DefaultDebounce,
+		observer: func(Refiltered) {},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Debounce() sets the window over which a join coalesces source
+// updates before refiltering.  A zero window refilters on every update.
+func Debounce(window time.Duration) Option {
+	return func(o *options) {
+		o.debounce = window
+	}
+}
+
+// Observer() makes a join call fn with the outcome of each refilter.
+func Observer(fn func(Refiltered)) Option {
+	return func(o *options) {
+		o.observer = fn
+	}
+}
+
+// Refiltered describes the outcome of a refilter by a join.
+type Refiltered struct {
+	// Filter is the filter that was computed from the sources.
+	Filter filter.Filter
+
+	// Err is set if the filter could not be computed or applied.
+	Err error
+}
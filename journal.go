@@ -0,0 +1,107 @@
+package kcache
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type journalEntry struct {
+	version int
+	evt     Event
+}
+
+// journal holds the most recent events of a controller along
+// with the resource version that produced them.  Events produced
+// by a list share the version of the list.
+type journal struct {
+	size int
+
+	// version preceding the oldest entry.  Replay is only
+	// possible from this version onwards.
+	base    int
+	started bool
+
+	entries []journalEntry
+	mtx     sync.Mutex
+}
+
+func newJournal(size int) *journal {
+	return &journal{size: size}
+}
+
+// reset() discards all entries and starts the journal at version.
+func (j *journal) reset(version string) error {
+	if j == nil {
+		return nil
+	}
+
+	vsn, err := strconv.Atoi(version)
+	if err != nil {
+		return errors.Wrap(err, "journal: version")
+	}
+
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	j.base = vsn
+	j.started = true
+	j.entries = nil
+	return nil
+}
+
+func (j *journal) record(evt Event) error {
+	if j == nil {
+		return nil
+	}
+
+	vsn, err := strconv.Atoi(eventVersion(evt))
+	if err != nil {
+		return errors.Wrap(err, "journal: version")
+	}
+
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	if !j.started {
+		return nil
+	}
+
+	j.entries = append(j.entries, journalEntry{vsn, evt})
+
+	if len(j.entries) > j.size {
+		j.base = j.entries[0].version
+		j.entries[0] = journalEntry{}
+		j.entries = j.entries[1:]
+	}
+	return nil
+}
+
+// since() returns the events journaled after version.  It returns
+// ErrVersionTooOld if events after version have been discarded.
+func (j *journal) since(version string) ([]Event, error) {
+	if j == nil {
+		return nil, errors.WithStack(ErrNoJournal)
+	}
+
+	vsn, err := strconv.Atoi(version)
+	if err != nil {
+		return nil, errors.Wrap(err, "journal: version")
+	}
+
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	if !j.started || vsn < j.base {
+		return nil, errors.WithStack(ErrVersionTooOld)
+	}
+
+	var events []Event
+	for _, entry := range j.entries {
+		if entry.version > vsn {
+			events = append(events, entry.evt)
+		}
+	}
+	return events, nil
+}
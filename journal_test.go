@@ -0,0 +1,146 @@
+package kcache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/boz/kcache/client/mocks"
+	"github.com/boz/kcache/testutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestJournal(t *testing.T) {
+	j := newJournal(3)
+
+	_, err := j.since("1")
+	assert.Equal(t, ErrVersionTooOld, errors.Cause(err))
+
+	require.NoError(t, j.reset("5"))
+
+	for _, vsn := range []string{"6", "7"} {
+		require.NoError(t, j.record(testGenEvent(EventTypeUpdate, "a", "b", vsn)))
+	}
+
+	// list events share the version of the list.
+	require.NoError(t, j.record(withVersion(testGenEvent(EventTypeDelete, "a", "c", "2"), "9")))
+
+	events, err := j.since("5")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"6", "7", "9"}, testEventVersions(events))
+
+	events, err = j.since("7")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"9"}, testEventVersions(events))
+
+	events, err = j.since("9")
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	// discarding "6" makes it the oldest version.
+	require.NoError(t, j.record(testGenEvent(EventTypeUpdate, "a", "b", "10")))
+
+	_, err = j.since("5")
+	assert.Equal(t, ErrVersionTooOld, errors.Cause(err))
+
+	events, err = j.since("6")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"7", "9", "10"}, testEventVersions(events))
+
+	_, err = j.since("x")
+	assert.Error(t, err)
+
+	var none *journal
+	_, err = none.since("5")
+	assert.Equal(t, ErrNoJournal, errors.Cause(err))
+}
+
+func TestController_SubscribeFrom(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventch := make(chan watch.Event, 10)
+
+	mwatch := &mocks.WatchInterface{}
+	mwatch.On("ResultChan").Return(eventch)
+	mwatch.On("Stop").Return()
+
+	list := &v1.PodList{
+		ListMeta: metav1.ListMeta{ResourceVersion: "5"},
+		Items:    []v1.Pod{*testGenPod("ns", "a", "1")},
+	}
+
+	client := &mocks.Client{}
+	client.On("Watch", mock.Anything, mock.AnythingOfType("v1.ListOptions")).Return(mwatch, nil)
+	client.On("List", mock.Anything, mock.AnythingOfType("v1.ListOptions")).Return(list, nil)
+
+	controller, err := NewBuilder().
+		Context(ctx).
+		Client(client).
+		Journal(2).
+		Create()
+	require.NoError(t, err)
+
+	testutil.AssertReady(t, "controller", controller)
+
+	sub, err := controller.Subscribe()
+	require.NoError(t, err)
+
+	eventch <- watch.Event{Type: watch.Added, Object: testGenPod("ns", "b", "6")}
+	eventch <- watch.Event{Type: watch.Modified, Object: testGenPod("ns", "a", "7")}
+
+	testReadEvents(t, ctx, sub, 2)
+
+	replay, err := controller.SubscribeFrom("5")
+	require.NoError(t, err)
+	testutil.AssertReady(t, "replay", replay)
+
+	events := testReadEvents(t, ctx, replay, 2)
+	assert.Equal(t, []string{"6", "7"}, testEventVersions(events))
+	assert.Equal(t, EventTypeCreate, events[0].Type())
+
+	// live events follow the replay.
+	eventch <- watch.Event{Type: watch.Deleted, Object: testGenPod("ns", "b", "8")}
+
+	events = testReadEvents(t, ctx, replay, 1)
+	assert.Equal(t, EventTypeDelete, events[0].Type())
+	testReadEvents(t, ctx, sub, 1)
+
+	_, err = controller.SubscribeFrom("5")
+	assert.Equal(t, ErrVersionTooOld, errors.Cause(err))
+
+	clone, err := controller.Clone()
+	require.NoError(t, err)
+	_, err = clone.SubscribeFrom("5")
+	assert.Equal(t, ErrNoJournal, errors.Cause(err))
+
+	controller.Close()
+	testutil.AssertDone(t, "controller", controller)
+}
+
+func testReadEvents(t *testing.T, ctx context.Context, sub Subscription, count int) []Event {
+	var events []Event
+	for len(events) < count {
+		select {
+		case evt := <-sub.Events():
+			events = append(events, evt)
+		case <-testutil.AsyncWaitch(ctx):
+			require.Fail(t, "missing events", "%v", events)
+		}
+	}
+	return events
+}
+
+func testEventVersions(events []Event) []string {
+	var versions []string
+	for _, evt := range events {
+		versions = append(versions, eventVersion(evt))
+	}
+	return versions
+}
@@ -0,0 +1,365 @@
+// Package kcachetest provides an in-memory API server for
+// testing code built on kcache.
+//
+//	server := kcachetest.NewServer()
+//
+//	controller, err := pod.BuildController(ctx, log, server.Client(&corev1.Pod{}, ""))
+//
+//	err = server.Create(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1"}})
+//
+// Objects of every kind share a single resource version sequence.  Watches
+// resume from the version they are given, and fail with an expired error
+// if that version has been compacted.  Faults can be injected with
+// SetListError(), SetWatchError(), SetLatency() and DropWatches().
+package kcachetest
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/boz/kcache/client"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+type objectKey struct {
+	namespace string
+	name      string
+}
+
+type historyEntry struct {
+	version int
+	gvk     schema.GroupVersionKind
+	evt     watch.Event
+}
+
+// Server is an in-memory object store which serves
+// clients for any kind known to the client-go scheme.
+type Server struct {
+	version int
+
+	// history holds the events after compacted.
+	history   []historyEntry
+	compacted int
+
+	objects map[schema.GroupVersionKind]map[objectKey]runtime.Object
+	watches map[*serverWatch]struct{}
+
+	listErr  error
+	watchErr error
+	latency  time.Duration
+
+	mtx sync.Mutex
+}
+
+func NewServer() *Server {
+	return &Server{
+		objects: make(map[schema.GroupVersionKind]map[objectKey]runtime.Object),
+		watches: make(map[*serverWatch]struct{}),
+	}
+}
+
+// Client() returns a client for objects of the same kind as obj.
+// Only objects in ns are served, unless ns is empty.  It panics if
+// the kind of obj is not registered with the client-go scheme.
+func (s *Server) Client(obj runtime.Object, ns string) client.Client {
+	gvk, err := kindOf(obj)
+	if err != nil {
+		panic(err)
+	}
+	c := &serverClient{s, gvk, ns}
+	return client.NewClient(c.list, c.watch)
+}
+
+// Create() adds obj, setting its resource version.
+func (s *Server) Create(obj runtime.Object) error {
+	return s.modify(watch.Added, obj)
+}
+
+// Update() replaces obj, setting its resource version.
+func (s *Server) Update(obj runtime.Object) error {
+	return s.modify(watch.Modified, obj)
+}
+
+// Delete() removes obj, setting its resource version to the
+// version of the deletion.
+func (s *Server) Delete(obj runtime.Object) error {
+	return s.modify(watch.Deleted, obj)
+}
+
+// ResourceVersion() returns the current resource version.
+func (s *Server) ResourceVersion() string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return strconv.Itoa(s.version)
+}
+
+// Compact() discards the history of events.  Watches from
+// versions before the current version will fail as expired.
+func (s *Server) Compact() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.compacted = s.version
+	s.history = nil
+}
+
+// DropWatches() closes every open watch, as if its
+// connection to the server was lost.
+func (s *Server) DropWatches() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for w := range s.watches {
+		w.close()
+		delete(s.watches, w)
+	}
+}
+
+// SetListError() causes lists to fail with err until it is set to nil.
+func (s *Server) SetListError(err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.listErr = err
+}
+
+// SetWatchError() causes watches to fail with err until it is set to nil.
+func (s *Server) SetWatchError(err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.watchErr = err
+}
+
+// SetLatency() delays every list and watch call by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.latency = d
+}
+
+func (s *Server) modify(et watch.EventType, obj runtime.Object) error {
+	gvk, err := kindOf(obj)
+	if err != nil {
+		return err
+	}
+
+	mobj, err := meta.Accessor(obj)
+	if err != nil {
+		return errors.Wrap(err, "kcachetest: object")
+	}
+
+	key := objectKey{mobj.GetNamespace(), mobj.GetName()}
+	resource, _ := meta.UnsafeGuessKindToResource(gvk)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	objects, ok := s.objects[gvk]
+	if !ok {
+		objects = make(map[objectKey]runtime.Object)
+		s.objects[gvk] = objects
+	}
+
+	current, found := objects[key]
+
+	switch {
+	case et == watch.Added && found:
+		return apierrors.NewAlreadyExists(resource.GroupResource(), key.name)
+	case et != watch.Added && !found:
+		return apierrors.NewNotFound(resource.GroupResource(), key.name)
+	}
+
+	s.version++
+	vsn := strconv.Itoa(s.version)
+
+	if et == watch.Deleted {
+		obj = current
+		delete(objects, key)
+	}
+
+	stored := obj.DeepCopyObject()
+	if err := setResourceVersion(stored, vsn); err != nil {
+		return err
+	}
+	mobj.SetResourceVersion(vsn)
+
+	if et != watch.Deleted {
+		objects[key] = stored
+	}
+
+	entry := historyEntry{s.version, gvk, watch.Event{Type: et, Object: stored}}
+	s.history = append(s.history, entry)
+
+	for w := range s.watches {
+		w.send(entry)
+	}
+
+	return nil
+}
+
+func (s *Server) delay(ctx context.Context) error {
+	s.mtx.Lock()
+	latency := s.latency
+	s.mtx.Unlock()
+
+	if latency <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(latency)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type serverClient struct {
+	server *Server
+	gvk    schema.GroupVersionKind
+	ns     string
+}
+
+func (c *serverClient) list(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+	s := c.server
+
+	if err := s.delay(ctx); err != nil {
+		return nil, err
+	}
+
+	list, err := scheme.Scheme.New(c.gvk.GroupVersion().WithKind(c.gvk.Kind + "List"))
+	if err != nil {
+		return nil, errors.Wrap(err, "kcachetest: list kind")
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.listErr != nil {
+		return nil, s.listErr
+	}
+
+	if err := meta.SetList(list, s.list(c)); err != nil {
+		return nil, errors.Wrap(err, "kcachetest: list")
+	}
+
+	accessor, err := meta.ListAccessor(list)
+	if err != nil {
+		return nil, errors.Wrap(err, "kcachetest: list")
+	}
+	accessor.SetResourceVersion(strconv.Itoa(s.version))
+
+	return list, nil
+}
+
+func (c *serverClient) watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	s := c.server
+
+	if err := s.delay(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.watchErr != nil {
+		return nil, s.watchErr
+	}
+
+	// without a version, current objects are sent as added.
+	if opts.ResourceVersion == "" || opts.ResourceVersion == "0" {
+		w := newServerWatch(c)
+		for _, obj := range s.list(c) {
+			w.send(historyEntry{s.version, c.gvk, watch.Event{Type: watch.Added, Object: obj}})
+		}
+		s.watches[w] = struct{}{}
+		return w, nil
+	}
+
+	version, err := strconv.Atoi(opts.ResourceVersion)
+	if err != nil {
+		return nil, apierrors.NewBadRequest("invalid resource version: " + opts.ResourceVersion)
+	}
+
+	w := newServerWatch(c)
+
+	if version < s.compacted {
+		w.send(historyEntry{evt: watch.Event{Type: watch.Error, Object: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusGone,
+			Reason:  metav1.StatusReasonExpired,
+			Message: "too old resource version: " + opts.ResourceVersion,
+		}}})
+		w.close()
+		return w, nil
+	}
+
+	for _, entry := range s.history {
+		if entry.version > version {
+			w.send(entry)
+		}
+	}
+
+	s.watches[w] = struct{}{}
+
+	return w, nil
+}
+
+// list() returns copies of the objects served to c,
+// ordered by namespace and name.
+func (s *Server) list(c *serverClient) []runtime.Object {
+	keys := make([]objectKey, 0, len(s.objects[c.gvk]))
+	for key := range s.objects[c.gvk] {
+		if c.ns == "" || c.ns == key.namespace {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].namespace != keys[j].namespace {
+			return keys[i].namespace < keys[j].namespace
+		}
+		return keys[i].name < keys[j].name
+	})
+
+	items := make([]runtime.Object, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, s.objects[c.gvk][key].DeepCopyObject())
+	}
+	return items
+}
+
+// unwatch() removes w from the set of open watches.
+func (s *Server) unwatch(w *serverWatch) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.watches, w)
+}
+
+func kindOf(obj runtime.Object) (schema.GroupVersionKind, error) {
+	gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return schema.GroupVersionKind{}, errors.Wrap(err, "kcachetest: object kind")
+	}
+	return gvks[0], nil
+}
+
+func setResourceVersion(obj runtime.Object, version string) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return errors.Wrap(err, "kcachetest: object")
+	}
+	accessor.SetResourceVersion(version)
+	return nil
+}
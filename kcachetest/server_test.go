@@ -0,0 +1,182 @@
+package kcachetest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	logutil "github.com/boz/go-logutil"
+	"github.com/boz/kcache"
+	"github.com/boz/kcache/kcachetest"
+	"github.com/boz/kcache/testutil"
+	"github.com/boz/kcache/types/pod"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := kcachetest.NewServer()
+	client := server.Client(&corev1.Pod{}, "a")
+
+	pod_a := testGenPod("a", "pod-1")
+	require.NoError(t, server.Create(pod_a))
+	assert.Equal(t, "1", pod_a.ResourceVersion)
+
+	require.NoError(t, server.Create(testGenPod("b", "pod-2")))
+	require.NoError(t, server.Create(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a"}}))
+
+	assert.True(t, apierrors.IsAlreadyExists(server.Create(testGenPod("a", "pod-1"))))
+	assert.True(t, apierrors.IsNotFound(server.Update(testGenPod("a", "pod-3"))))
+
+	obj, err := client.List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	list := obj.(*corev1.PodList)
+	assert.Equal(t, "3", list.ResourceVersion)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "pod-1", list.Items[0].Name)
+
+	require.NoError(t, server.Update(testGenPod("a", "pod-1")))
+
+	// current objects are added without a version.
+	current, err := client.Watch(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	defer current.Stop()
+
+	evt := testReadEvent(t, current)
+	assert.Equal(t, watch.Added, evt.Type)
+	assert.Equal(t, "4", evt.Object.(*corev1.Pod).ResourceVersion)
+
+	// events after the given version are replayed.
+	w, err := client.Watch(ctx, metav1.ListOptions{ResourceVersion: "1"})
+	require.NoError(t, err)
+	defer w.Stop()
+
+	evt = testReadEvent(t, w)
+	assert.Equal(t, watch.Modified, evt.Type)
+	assert.Equal(t, "4", evt.Object.(*corev1.Pod).ResourceVersion)
+
+	require.NoError(t, server.Delete(testGenPod("a", "pod-1")))
+
+	evt = testReadEvent(t, w)
+	assert.Equal(t, watch.Deleted, evt.Type)
+	assert.Equal(t, "5", evt.Object.(*corev1.Pod).ResourceVersion)
+	assert.Equal(t, "5", server.ResourceVersion())
+
+	// compacted versions have expired.
+	server.Compact()
+
+	old, err := client.Watch(ctx, metav1.ListOptions{ResourceVersion: "3"})
+	require.NoError(t, err)
+	evt = testReadEvent(t, old)
+	assert.Equal(t, watch.Error, evt.Type)
+	assert.Equal(t, int32(http.StatusGone), evt.Object.(*metav1.Status).Code)
+	testWatchClosed(t, old)
+
+	server.DropWatches()
+	testReadEvent(t, current)
+	testWatchClosed(t, current)
+	testWatchClosed(t, w)
+
+	failure := errors.New("failure")
+
+	server.SetListError(failure)
+	_, err = client.List(ctx, metav1.ListOptions{})
+	assert.Equal(t, failure, err)
+	server.SetListError(nil)
+
+	server.SetWatchError(failure)
+	_, err = client.Watch(ctx, metav1.ListOptions{})
+	assert.Equal(t, failure, err)
+	server.SetWatchError(nil)
+
+	server.SetLatency(50 * time.Millisecond)
+	start := time.Now()
+	_, err = client.List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+}
+
+func TestServer_controller(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := kcachetest.NewServer()
+	require.NoError(t, server.Create(testGenPod("a", "pod-1")))
+
+	controller, err := pod.BuildController(ctx, logutil.Default(), server.Client(&corev1.Pod{}, ""))
+	require.NoError(t, err)
+
+	testutil.AssertReady(t, "controller", controller)
+
+	sub, err := controller.Subscribe()
+	require.NoError(t, err)
+
+	require.NoError(t, server.Create(testGenPod("a", "pod-2")))
+
+	select {
+	case evt := <-sub.Events():
+		assert.Equal(t, kcache.EventTypeCreate, evt.Type())
+		assert.Equal(t, "pod-2", evt.Resource().Name)
+	case <-time.After(time.Second):
+		require.Fail(t, "no event")
+	}
+
+	// the controller relists after its watch version expires.
+	server.DropWatches()
+	require.NoError(t, server.Delete(testGenPod("a", "pod-1")))
+	server.Compact()
+
+	select {
+	case evt := <-sub.Events():
+		assert.Equal(t, kcache.EventTypeDelete, evt.Type())
+		assert.Equal(t, "pod-1", evt.Resource().Name)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "no event")
+	}
+
+	pods, err := controller.Cache().List()
+	require.NoError(t, err)
+	require.Len(t, pods, 1)
+	assert.Equal(t, "pod-2", pods[0].Name)
+
+	controller.Close()
+	testutil.AssertDone(t, "controller", controller)
+}
+
+func testReadEvent(t *testing.T, w watch.Interface) watch.Event {
+	select {
+	case evt, ok := <-w.ResultChan():
+		require.True(t, ok, "watch closed")
+		return evt
+	case <-time.After(time.Second):
+		require.Fail(t, "no event")
+		return watch.Event{}
+	}
+}
+
+func testWatchClosed(t *testing.T, w watch.Interface) {
+	select {
+	case _, ok := <-w.ResultChan():
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		require.Fail(t, "watch not closed")
+	}
+}
+
+func testGenPod(ns, name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+		},
+	}
+}
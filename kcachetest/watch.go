@@ -0,0 +1,119 @@
+package kcachetest
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// serverWatch delivers the events of a kind to a client.  Events
+// are queued so that the server never waits on a slow client.
+type serverWatch struct {
+	client *serverClient
+
+	resultch chan watch.Event
+	stopch   chan struct{}
+	notifych chan struct{}
+	once     sync.Once
+
+	queue  []watch.Event
+	closed bool
+	mtx    sync.Mutex
+}
+
+func newServerWatch(c *serverClient) *serverWatch {
+	w := &serverWatch{
+		client:   c,
+		resultch: make(chan watch.Event),
+		stopch:   make(chan struct{}),
+		notifych: make(chan struct{}, 1),
+	}
+	go w.run()
+	return w
+}
+
+func (w *serverWatch) ResultChan() <-chan watch.Event {
+	return w.resultch
+}
+
+func (w *serverWatch) Stop() {
+	w.once.Do(func() {
+		close(w.stopch)
+		w.client.server.unwatch(w)
+	})
+}
+
+// send() queues the event of entry if it is of the watched kind
+// and namespace.  Errors are sent regardless.
+func (w *serverWatch) send(entry historyEntry) {
+	if entry.evt.Type != watch.Error {
+		if entry.gvk != w.client.gvk {
+			return
+		}
+		if ns := w.client.ns; ns != "" && ns != namespaceOf(entry.evt) {
+			return
+		}
+	}
+
+	w.mtx.Lock()
+	w.queue = append(w.queue, watch.Event{Type: entry.evt.Type, Object: entry.evt.Object.DeepCopyObject()})
+	w.mtx.Unlock()
+
+	w.notify()
+}
+
+// close() closes the watch once its queued events are delivered.
+func (w *serverWatch) close() {
+	w.mtx.Lock()
+	w.closed = true
+	w.mtx.Unlock()
+
+	w.notify()
+}
+
+func (w *serverWatch) notify() {
+	select {
+	case w.notifych <- struct{}{}:
+	default:
+	}
+}
+
+func (w *serverWatch) run() {
+	defer close(w.resultch)
+
+	for {
+		w.mtx.Lock()
+		queue, closed := w.queue, w.closed
+		w.queue = nil
+		w.mtx.Unlock()
+
+		for _, evt := range queue {
+			select {
+			case w.resultch <- evt:
+			case <-w.stopch:
+				return
+			}
+		}
+
+		if len(queue) > 0 {
+			continue
+		}
+
+		if closed {
+			return
+		}
+
+		select {
+		case <-w.notifych:
+		case <-w.stopch:
+			return
+		}
+	}
+}
+
+func namespaceOf(evt watch.Event) string {
+	if obj, ok := evt.Object.(interface{ GetNamespace() string }); ok {
+		return obj.GetNamespace()
+	}
+	return ""
+}
@@ -26,6 +26,7 @@ const (
 
 type lister interface {
 	Result() <-chan listResult
+	refresh()
 	Done() <-chan struct{}
 	Error() error
 }
@@ -36,25 +37,34 @@ type listResult struct {
 }
 
 type _lister struct {
-	client   client.ListClient
-	period   time.Duration
-	resultch chan listResult
+	client    client.ListClient
+	period    time.Duration
+	warm      bool
+	resultch  chan listResult
+	refreshch chan struct{}
+
+	env environment
 
 	log logutil.Log
 	lc  lifecycle.Lifecycle
 	ctx context.Context
 }
 
-func newLister(ctx context.Context, log logutil.Log, stopch <-chan struct{}, period time.Duration, client client.ListClient) *_lister {
+// newLister() returns a lister which lists immediately and then every period.
+// When warm, the first list waits for the period to pass.
+func newLister(ctx context.Context, log logutil.Log, env environment, stopch <-chan struct{}, period time.Duration, client client.ListClient, warm bool) *_lister {
 	log = log.WithComponent("lister")
 
 	l := &_lister{
-		client:   client,
-		period:   period,
-		resultch: make(chan listResult),
-		log:      log,
-		lc:       lifecycle.New(),
-		ctx:      ctx,
+		client:    client,
+		period:    period,
+		warm:      warm,
+		resultch:  make(chan listResult),
+		refreshch: make(chan struct{}),
+		env:       env,
+		log:       log,
+		lc:        lifecycle.New(),
+		ctx:       ctx,
 	}
 
 	go l.lc.WatchContext(ctx)
@@ -69,6 +79,14 @@ func (l *_lister) Result() <-chan listResult {
 	return l.resultch
 }
 
+// refresh() requests a list now, unless one is already pending.
+func (l *_lister) refresh() {
+	select {
+	case l.refreshch <- struct{}{}:
+	case <-l.lc.ShuttingDown():
+	}
+}
+
 func (l *_lister) Done() <-chan struct{} {
 	return l.lc.Done()
 }
@@ -83,11 +101,21 @@ func (l *_lister) run() {
 	var resultch chan listResult
 	var result listResult
 
-	runch, donech := l.list()
+	var runch <-chan listResult
+	var donech <-chan struct{}
 
-	ticker := newTicker(l.period, defaultRefreshFuzz)
+	ticker := newTicker(l.env.clock, l.period, defaultRefreshFuzz)
 	var tickch <-chan int
 
+	if l.warm {
+		closed := make(chan struct{})
+		close(closed)
+		donech = closed
+		tickch = ticker.Next()
+	} else {
+		runch, donech = l.list()
+	}
+
 mainloop:
 	for {
 		select {
@@ -95,6 +123,13 @@ mainloop:
 			runch, donech = l.list()
 			tickch = nil
 
+		case <-l.refreshch:
+			if runch != nil || resultch != nil {
+				continue
+			}
+			runch, donech = l.list()
+			tickch = nil
+
 		case result = <-runch:
 			resultch = l.resultch
 			runch = nil
@@ -137,19 +172,33 @@ func (l *_lister) list() (<-chan listResult, <-chan struct{}) {
 }
 
 func (l *_lister) executeList(ctx context.Context) listResult {
+	ctx, span := l.env.tracer.Start(ctx, "kcache.list",
+		StringAttribute(AttributeNamespace, clientNamespace(l.client)))
+
+	start := l.env.clock.Now()
 	list, err := l.client.List(ctx, v1.ListOptions{})
+	l.env.metrics.ListCompleted(l.env.clock.Since(start), err)
 
 	if err != nil {
 		if err != context.Canceled {
 			l.log.Errorf("client list: %v", err)
 		}
+		span.End(err)
 		return listResult{nil, errors.Wrap(err, "client list")}
 	}
 
 	if _, ok := list.(meta.List); !ok {
 		l.log.Errorf("invalid type: %T", list)
+		span.End(errInvalidType)
 		return listResult{nil, errors.WithStack(errInvalidType)}
 	}
 
+	version, _ := listResourceVersion(list)
+	span.SetAttributes(
+		StringAttribute(AttributeResource, resourceName(list)),
+		StringAttribute(AttributeResourceVersion, version),
+		IntAttribute(AttributeObjects, meta.LenList(list)))
+	span.End(nil)
+
 	return listResult{list, nil}
 }
@@ -0,0 +1,58 @@
+package kcache
+
+import "time"
+
+// Metrics receives measurements from a controller and from every
+// subscription, publisher and cache derived from it.
+//
+// Implementations must be safe for concurrent use.  Gauges are reported
+// as deltas so that a single Metrics can be shared by any number of
+// caches and publishers.  Gauges are reported with the kind of node
+// they belong to: "controller" for the controller's own cache and
+// publisher, "filter-subscription" and "publisher" for those derived
+// from it.
+type Metrics interface {
+	// ListCompleted() is called after every list request.
+	ListCompleted(duration time.Duration, err error)
+
+	// WatchReconnected() is called when a watch session is restarted
+	// after it ended on its own.
+	WatchReconnected()
+
+	// WatchSessionEnded() is called when a watch session is done.
+	WatchSessionEnded(duration time.Duration, err error)
+
+	// EventProcessed() is called for each event the controller distributes.
+	EventProcessed(EventType)
+
+	// CacheResized() is called with the change in a cache's object count.
+	CacheResized(node string, delta int)
+
+	// SubscribersChanged() is called with the change in a publisher's
+	// subscription count.
+	SubscribersChanged(node string, delta int)
+
+	// BufferFill() is called with the length and capacity of an event
+	// buffer after an event is queued.
+	BufferFill(length, capacity int)
+
+	// EventDropped() is called when an event is dropped because a
+	// buffer was full.
+	EventDropped()
+}
+
+// NullMetrics() returns a Metrics which discards all measurements.
+func NullMetrics() Metrics {
+	return nullMetrics{}
+}
+
+type nullMetrics struct{}
+
+func (nullMetrics) ListCompleted(time.Duration, error)     {}
+func (nullMetrics) WatchReconnected()                      {}
+func (nullMetrics) WatchSessionEnded(time.Duration, error) {}
+func (nullMetrics) EventProcessed(EventType)               {}
+func (nullMetrics) CacheResized(string, int)               {}
+func (nullMetrics) SubscribersChanged(string, int)         {}
+func (nullMetrics) BufferFill(int, int)                    {}
+func (nullMetrics) EventDropped()                          {}
@@ -0,0 +1,172 @@
+package kcache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/boz/kcache/client/mocks"
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	"github.com/boz/kcache/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestController_metrics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventch := make(chan watch.Event, 10)
+
+	mwatch := &mocks.WatchInterface{}
+	mwatch.On("ResultChan").Return(eventch)
+	mwatch.On("Stop").Return()
+
+	list := &v1.PodList{
+		ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+		Items:    []v1.Pod{*testGenPod("ns", "a", "1"), *testGenPod("ns", "b", "2")},
+	}
+
+	client := &mocks.Client{}
+	client.On("Watch", mock.Anything, mock.AnythingOfType("v1.ListOptions")).Return(mwatch, nil)
+	client.On("List", mock.Anything, mock.AnythingOfType("v1.ListOptions")).Return(list, nil)
+
+	metrics := &testMetrics{
+		events:      make(map[EventType]int),
+		objects:     make(map[string]int),
+		subscribers: make(map[string]int),
+	}
+
+	controller, err := NewBuilder().
+		Context(ctx).
+		Client(client).
+		Metrics(metrics).
+		Create()
+	require.NoError(t, err)
+
+	testutil.AssertReady(t, "controller", controller)
+
+	sub, err := controller.Subscribe()
+	require.NoError(t, err)
+
+	fsub, err := controller.SubscribeWithFilter(filter.NSName(nsname.New("ns", "a")))
+	require.NoError(t, err)
+	testutil.AssertReady(t, "fsub", fsub)
+
+	eventch <- watch.Event{Type: watch.Added, Object: testGenPod("ns", "c", "3")}
+
+	select {
+	case <-sub.Events():
+	case <-testutil.AsyncWaitch(ctx):
+		require.Fail(t, "no event")
+	}
+
+	assert.Eventually(t, func() bool {
+		snap := metrics.snapshot()
+		return snap.lists == 1 &&
+			snap.objects["controller"] == 3 &&
+			snap.objects["filter-subscription"] == 1 &&
+			snap.subscribers["controller"] == 2 &&
+			snap.events[EventTypeCreate] == 1 &&
+			snap.fills > 0
+	}, time.Second, 10*time.Millisecond)
+
+	sub.Close()
+	testutil.AssertDone(t, "sub", sub)
+	fsub.Close()
+	testutil.AssertDone(t, "fsub", fsub)
+
+	assert.Eventually(t, func() bool {
+		snap := metrics.snapshot()
+		return snap.subscribers["controller"] == 0 && snap.objects["filter-subscription"] == 0
+	}, time.Second, 10*time.Millisecond)
+
+	controller.Close()
+	testutil.AssertDone(t, "controller", controller)
+
+	snap := metrics.snapshot()
+	assert.Equal(t, 0, snap.objects["controller"])
+	assert.Equal(t, 1, snap.sessions)
+}
+
+type testMetrics struct {
+	lists       int
+	sessions    int
+	events      map[EventType]int
+	objects     map[string]int
+	subscribers map[string]int
+	fills       int
+	mtx         sync.Mutex
+}
+
+func (m *testMetrics) snapshot() testMetrics {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	events := make(map[EventType]int, len(m.events))
+	for k, v := range m.events {
+		events[k] = v
+	}
+	objects := make(map[string]int, len(m.objects))
+	for k, v := range m.objects {
+		objects[k] = v
+	}
+	subscribers := make(map[string]int, len(m.subscribers))
+	for k, v := range m.subscribers {
+		subscribers[k] = v
+	}
+	return testMetrics{
+		lists:       m.lists,
+		sessions:    m.sessions,
+		events:      events,
+		objects:     objects,
+		subscribers: subscribers,
+		fills:       m.fills,
+	}
+}
+
+func (m *testMetrics) ListCompleted(time.Duration, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.lists++
+}
+
+func (m *testMetrics) WatchReconnected() {}
+
+func (m *testMetrics) WatchSessionEnded(time.Duration, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.sessions++
+}
+
+func (m *testMetrics) EventProcessed(et EventType) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.events[et]++
+}
+
+func (m *testMetrics) CacheResized(node string, delta int) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.objects[node] += delta
+}
+
+func (m *testMetrics) SubscribersChanged(node string, delta int) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.subscribers[node] += delta
+}
+
+func (m *testMetrics) BufferFill(int, int) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.fills++
+}
+
+func (m *testMetrics) EventDropped() {}
@@ -18,7 +18,7 @@ func TestMonitor(t *testing.T) {
 
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	publisher := newPublisher(log, parent)
+	publisher := newPublisher(log, defaultEnvironment(), parent)
 	defer parent.Close()
 
 	icalled := make(chan bool)
@@ -46,7 +46,7 @@ func TestMonitor(t *testing.T) {
 		close(dcalled)
 	}).Create()
 
-	cache.sync([]metav1.Object{testGenPod("a", "b", "1")})
+	cache.sync("", []metav1.Object{testGenPod("a", "b", "1")})
 
 	m, err := NewMonitor(publisher, h)
 	assert.NoError(t, err)
@@ -91,7 +91,7 @@ func TestMonitor_lifecycle_close_early(t *testing.T) {
 
 	log := logutil.Default()
 	parent, _, readych := testNewSubscription(t, log, filter.Null())
-	publisher := newPublisher(log, parent)
+	publisher := newPublisher(log, defaultEnvironment(), parent)
 
 	calledch := make(chan bool)
 
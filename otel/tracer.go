@@ -0,0 +1,69 @@
+// Package otel records kcache spans with OpenTelemetry.
+//
+//	controller, err := kcache.NewBuilder().
+//	  Tracer(otel.NewTracer(otelapi.Tracer("kcache"))).
+//	  Client(client).
+//	  Create()
+//
+// It is a separate module so that kcache itself does not
+// depend on OpenTelemetry.
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/boz/kcache"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewTracer() returns a kcache.Tracer which starts its spans with tracer.
+func NewTracer(tracer trace.Tracer) kcache.Tracer {
+	return &otelTracer{tracer}
+}
+
+type otelTracer struct {
+	tracer trace.Tracer
+}
+
+func (t *otelTracer) Start(ctx context.Context, name string, attrs ...kcache.Attribute) (context.Context, kcache.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(convert(attrs)...))
+	return ctx, otelSpan{span}
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s otelSpan) SetAttributes(attrs ...kcache.Attribute) {
+	s.span.SetAttributes(convert(attrs)...)
+}
+
+func (s otelSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+func convert(attrs []kcache.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		switch value := attr.Value.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(attr.Key, value))
+		case int:
+			kvs = append(kvs, attribute.Int(attr.Key, value))
+		case int64:
+			kvs = append(kvs, attribute.Int64(attr.Key, value))
+		case bool:
+			kvs = append(kvs, attribute.Bool(attr.Key, value))
+		default:
+			kvs = append(kvs, attribute.String(attr.Key, fmt.Sprint(value)))
+		}
+	}
+	return kvs
+}
@@ -0,0 +1,46 @@
+package otel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/boz/kcache"
+	"github.com/boz/kcache/otel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	tracer := otel.NewTracer(provider.Tracer("kcache"))
+
+	ctx, parent := tracer.Start(context.Background(), "parent",
+		kcache.StringAttribute(kcache.AttributeNamespace, "ns"))
+	_, child := tracer.Start(ctx, "child")
+	child.SetAttributes(kcache.IntAttribute(kcache.AttributeEvents, 2))
+	child.End(errors.New("boom"))
+	parent.End(nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, spans[0].Attributes(), attribute.Int(kcache.AttributeEvents, 2))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "boom", spans[0].Status().Description)
+
+	assert.Equal(t, "parent", spans[1].Name())
+	assert.Contains(t, spans[1].Attributes(), attribute.String(kcache.AttributeNamespace, "ns"))
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+
+	assert.Equal(t, trace.SpanFromContext(ctx).SpanContext().SpanID(), spans[1].SpanContext().SpanID())
+}
@@ -0,0 +1,206 @@
+// Package prometheus reports kcache metrics to Prometheus.
+//
+//	collector := prometheus.NewCollector()
+//	registry.MustRegister(collector)
+//
+//	controller, err := kcache.NewBuilder().
+//	  Metrics(collector.Metrics("pods")).
+//	  Client(client).
+//	  Create()
+//
+// It is a separate module so that kcache itself does not
+// depend on the Prometheus client.
+package prometheus
+
+import (
+	"time"
+
+	"github.com/boz/kcache"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	listBuckets    = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	sessionBuckets = []float64{1, 10, 30, 60, 300, 600, 1800, 3600}
+	fillBuckets    = []float64{.1, .25, .5, .75, .9, 1}
+
+	eventTypes = []kcache.EventType{
+		kcache.EventTypeCreate,
+		kcache.EventTypeUpdate,
+		kcache.EventTypeDelete,
+	}
+)
+
+// Collector is a prometheus.Collector for the metrics of
+// any number of controllers, each labeled by name.
+type Collector struct {
+	listDuration    *prom.HistogramVec
+	listErrors      *prom.CounterVec
+	watchReconnects *prom.CounterVec
+	sessionDuration *prom.HistogramVec
+	sessionErrors   *prom.CounterVec
+	events          *prom.CounterVec
+	cacheObjects    *prom.GaugeVec
+	subscribers     *prom.GaugeVec
+	bufferFill      *prom.HistogramVec
+	eventsDropped   *prom.CounterVec
+}
+
+func NewCollector() *Collector {
+	controller := []string{"controller"}
+
+	return &Collector{
+		listDuration: prom.NewHistogramVec(prom.HistogramOpts{
+			Name:    "kcache_list_duration_seconds",
+			Help:    "Duration of list requests.",
+			Buckets: listBuckets,
+		}, controller),
+		listErrors: prom.NewCounterVec(prom.CounterOpts{
+			Name: "kcache_list_errors_total",
+			Help: "Number of failed list requests.",
+		}, controller),
+		watchReconnects: prom.NewCounterVec(prom.CounterOpts{
+			Name: "kcache_watch_reconnects_total",
+			Help: "Number of watch sessions restarted after ending on their own.",
+		}, controller),
+		sessionDuration: prom.NewHistogramVec(prom.HistogramOpts{
+			Name:    "kcache_watch_session_duration_seconds",
+			Help:    "Length of watch sessions.",
+			Buckets: sessionBuckets,
+		}, controller),
+		sessionErrors: prom.NewCounterVec(prom.CounterOpts{
+			Name: "kcache_watch_session_errors_total",
+			Help: "Number of watch sessions which ended with an error.",
+		}, controller),
+		events: prom.NewCounterVec(prom.CounterOpts{
+			Name: "kcache_events_total",
+			Help: "Number of events processed, by type.",
+		}, []string{"controller", "type"}),
+		cacheObjects: prom.NewGaugeVec(prom.GaugeOpts{
+			Name: "kcache_cache_objects",
+			Help: "Number of objects held in caches, by node kind.",
+		}, []string{"controller", "node"}),
+		subscribers: prom.NewGaugeVec(prom.GaugeOpts{
+			Name: "kcache_subscribers",
+			Help: "Number of active subscriptions, by node kind.",
+		}, []string{"controller", "node"}),
+		bufferFill: prom.NewHistogramVec(prom.HistogramOpts{
+			Name:    "kcache_buffer_fill_ratio",
+			Help:    "Fill level of event buffers after an event is queued.",
+			Buckets: fillBuckets,
+		}, controller),
+		eventsDropped: prom.NewCounterVec(prom.CounterOpts{
+			Name: "kcache_events_dropped_total",
+			Help: "Number of events dropped because a buffer was full.",
+		}, controller),
+	}
+}
+
+func (c *Collector) collectors() []prom.Collector {
+	return []prom.Collector{
+		c.listDuration,
+		c.listErrors,
+		c.watchReconnects,
+		c.sessionDuration,
+		c.sessionErrors,
+		c.events,
+		c.cacheObjects,
+		c.subscribers,
+		c.bufferFill,
+		c.eventsDropped,
+	}
+}
+
+// Describe() implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+	for _, collector := range c.collectors() {
+		collector.Describe(ch)
+	}
+}
+
+// Collect() implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	for _, collector := range c.collectors() {
+		collector.Collect(ch)
+	}
+}
+
+// Metrics() returns the kcache.Metrics for the controller with the given name.
+// Repeated calls with the same name report to the same series.
+func (c *Collector) Metrics(controller string) kcache.Metrics {
+	labels := prom.Labels{"controller": controller}
+
+	m := &metrics{
+		listDuration:    c.listDuration.With(labels),
+		listErrors:      c.listErrors.With(labels),
+		watchReconnects: c.watchReconnects.With(labels),
+		sessionDuration: c.sessionDuration.With(labels),
+		sessionErrors:   c.sessionErrors.With(labels),
+		events:          c.events.MustCurryWith(labels),
+		cacheObjects:    c.cacheObjects.MustCurryWith(labels),
+		subscribers:     c.subscribers.MustCurryWith(labels),
+		bufferFill:      c.bufferFill.With(labels),
+		eventsDropped:   c.eventsDropped.With(labels),
+	}
+
+	// report every event type, even before it occurs.
+	for _, et := range eventTypes {
+		m.events.WithLabelValues(string(et))
+	}
+
+	return m
+}
+
+type metrics struct {
+	listDuration    prom.Observer
+	listErrors      prom.Counter
+	watchReconnects prom.Counter
+	sessionDuration prom.Observer
+	sessionErrors   prom.Counter
+	events          *prom.CounterVec
+	cacheObjects    *prom.GaugeVec
+	subscribers     *prom.GaugeVec
+	bufferFill      prom.Observer
+	eventsDropped   prom.Counter
+}
+
+func (m *metrics) ListCompleted(d time.Duration, err error) {
+	m.listDuration.Observe(d.Seconds())
+	if err != nil {
+		m.listErrors.Inc()
+	}
+}
+
+func (m *metrics) WatchReconnected() {
+	m.watchReconnects.Inc()
+}
+
+func (m *metrics) WatchSessionEnded(d time.Duration, err error) {
+	m.sessionDuration.Observe(d.Seconds())
+	if err != nil {
+		m.sessionErrors.Inc()
+	}
+}
+
+func (m *metrics) EventProcessed(et kcache.EventType) {
+	m.events.WithLabelValues(string(et)).Inc()
+}
+
+func (m *metrics) CacheResized(node string, delta int) {
+	m.cacheObjects.WithLabelValues(node).Add(float64(delta))
+}
+
+func (m *metrics) SubscribersChanged(node string, delta int) {
+	m.subscribers.WithLabelValues(node).Add(float64(delta))
+}
+
+func (m *metrics) BufferFill(length, capacity int) {
+	if capacity <= 0 {
+		return
+	}
+	m.bufferFill.Observe(float64(length) / float64(capacity))
+}
+
+func (m *metrics) EventDropped() {
+	m.eventsDropped.Inc()
+}
@@ -0,0 +1,73 @@
+package prometheus_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/boz/kcache"
+	"github.com/boz/kcache/prometheus"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	collector := prometheus.NewCollector()
+
+	registry := prom.NewPedanticRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	pods := collector.Metrics("pods")
+
+	pods.ListCompleted(20*time.Millisecond, nil)
+	pods.ListCompleted(2*time.Second, errors.New("boom"))
+	pods.WatchReconnected()
+	pods.WatchSessionEnded(time.Minute, nil)
+	pods.EventProcessed(kcache.EventTypeCreate)
+	collector.Metrics("pods").EventProcessed(kcache.EventTypeCreate)
+	pods.EventProcessed(kcache.EventTypeDelete)
+	pods.CacheResized("controller", 3)
+	pods.CacheResized("controller", -1)
+	pods.CacheResized("filter-subscription", 1)
+	pods.SubscribersChanged("controller", 2)
+	pods.SubscribersChanged("publisher", 1)
+	pods.BufferFill(50, 100)
+	pods.EventDropped()
+
+	collector.Metrics(`we"ird`).CacheResized("controller", 1)
+
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).
+		ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	out := rec.Body.String()
+	for _, line := range []string{
+		`# TYPE kcache_list_duration_seconds histogram`,
+		`kcache_list_duration_seconds_bucket{controller="pods",le="0.025"} 1`,
+		`kcache_list_duration_seconds_bucket{controller="pods",le="2.5"} 2`,
+		`kcache_list_duration_seconds_bucket{controller="pods",le="+Inf"} 2`,
+		`kcache_list_duration_seconds_sum{controller="pods"} 2.02`,
+		`kcache_list_duration_seconds_count{controller="pods"} 2`,
+		`kcache_list_errors_total{controller="pods"} 1`,
+		`kcache_watch_reconnects_total{controller="pods"} 1`,
+		`kcache_watch_session_duration_seconds_count{controller="pods"} 1`,
+		`kcache_watch_session_errors_total{controller="pods"} 0`,
+		`kcache_events_total{controller="pods",type="create"} 2`,
+		`kcache_events_total{controller="pods",type="update"} 0`,
+		`kcache_events_total{controller="pods",type="delete"} 1`,
+		`# TYPE kcache_cache_objects gauge`,
+		`kcache_cache_objects{controller="pods",node="controller"} 2`,
+		`kcache_cache_objects{controller="pods",node="filter-subscription"} 1`,
+		`kcache_cache_objects{controller="we\"ird",node="controller"} 1`,
+		`kcache_subscribers{controller="pods",node="controller"} 2`,
+		`kcache_subscribers{controller="pods",node="publisher"} 1`,
+		`kcache_buffer_fill_ratio_bucket{controller="pods",le="0.25"} 0`,
+		`kcache_buffer_fill_ratio_bucket{controller="pods",le="0.5"} 1`,
+		`kcache_events_dropped_total{controller="pods"} 1`,
+	} {
+		assert.Contains(t, out, line+"\n")
+	}
+}
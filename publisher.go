@@ -10,29 +10,67 @@ import (
 type FilterController interface {
 	Controller
 	Refilter(filter.Filter) error
+
+	// Filter() returns the filter currently applied.
+	Filter() filter.Filter
+
+	// CloseWithError() closes the controller and sets its Error().
+	CloseWithError(error)
 }
 
 type publisher struct {
 	parent Subscription
 
-	subscribech   chan chan<- Subscription
-	unsubscribech chan subscription
-	subscriptions map[subscription]struct{}
+	// journal is nil unless the publisher is a controller's.
+	journal *journal
+
+	// kind of the publisher, for metrics.
+	kind string
+
+	subscribech     chan chan<- Subscription
+	subscribefromch chan subscribeFromRequest
+	unsubscribech   chan subscription
+	subscriptions   map[subscription]struct{}
+
+	env  environment
+	node *topologyNode
 
 	lc  lifecycle.Lifecycle
 	log logutil.Log
 }
 
-func newPublisher(log logutil.Log, parent Subscription) Controller {
+type subscribeFromRequest struct {
+	version  string
+	resultch chan<- subscribeFromResult
+}
+
+type subscribeFromResult struct {
+	sub Subscription
+	err error
+}
+
+func newPublisher(log logutil.Log, env environment, parent Subscription) Controller {
+	return newJournalPublisher(log, env, "publisher", parent, nil)
+}
+
+// newJournalPublisher() creates a publisher which records events
+// in journal for SubscribeFrom().
+func newJournalPublisher(log logutil.Log, env environment, kind string, parent Subscription, journal *journal) Controller {
 	s := &publisher{
-		parent:        parent,
-		subscribech:   make(chan chan<- Subscription),
-		unsubscribech: make(chan subscription),
-		subscriptions: make(map[subscription]struct{}),
-		lc:            lifecycle.New(),
-		log:           log.WithComponent("publisher"),
+		parent:          parent,
+		journal:         journal,
+		kind:            kind,
+		subscribech:     make(chan chan<- Subscription),
+		subscribefromch: make(chan subscribeFromRequest),
+		unsubscribech:   make(chan subscription),
+		subscriptions:   make(map[subscription]struct{}),
+		env:             env,
+		lc:              lifecycle.New(),
+		log:             log.WithComponent("publisher"),
 	}
 
+	s.node = nodeOf(parent).add("publisher", s)
+
 	go s.run()
 
 	return s
@@ -55,7 +93,14 @@ func (s *publisher) Done() <-chan struct{} {
 }
 
 func (s *publisher) Error() error {
-	return s.lc.Error()
+	if err := s.lc.Error(); err != nil {
+		return err
+	}
+	return s.parent.Error()
+}
+
+func (s *publisher) topologyNode() *topologyNode {
+	return s.node
 }
 
 func (s *publisher) Subscribe() (Subscription, error) {
@@ -68,12 +113,27 @@ func (s *publisher) Subscribe() (Subscription, error) {
 	}
 }
 
+func (s *publisher) SubscribeFrom(version string) (Subscription, error) {
+	if s.journal == nil {
+		return nil, errors.WithStack(ErrNoJournal)
+	}
+
+	resultch := make(chan subscribeFromResult, 1)
+	select {
+	case <-s.lc.ShuttingDown():
+		return nil, errors.WithStack(ErrNotRunning)
+	case s.subscribefromch <- subscribeFromRequest{version, resultch}:
+		result := <-resultch
+		return result.sub, result.err
+	}
+}
+
 func (s *publisher) SubscribeWithFilter(f filter.Filter) (FilterSubscription, error) {
 	sub, err := s.Subscribe()
 	if err != nil {
 		return nil, err
 	}
-	return newFilterSubscription(s.log, sub, f, false), nil
+	return newFilterSubscription(s.log, s.env, sub, f, false), nil
 }
 
 func (s *publisher) SubscribeForFilter() (FilterSubscription, error) {
@@ -81,7 +141,7 @@ func (s *publisher) SubscribeForFilter() (FilterSubscription, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newFilterSubscription(s.log, sub, filter.All(), true), nil
+	return newFilterSubscription(s.log, s.env, sub, filter.All(), true), nil
 }
 
 func (s *publisher) Clone() (Controller, error) {
@@ -89,7 +149,7 @@ func (s *publisher) Clone() (Controller, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newPublisher(s.log, sub), nil
+	return newPublisher(s.log, s.env, sub), nil
 }
 
 func (s *publisher) CloneWithFilter(f filter.Filter) (FilterController, error) {
@@ -97,7 +157,7 @@ func (s *publisher) CloneWithFilter(f filter.Filter) (FilterController, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newFilterPublisher(s.log, sub), nil
+	return newFilterPublisher(s.log, s.env, sub), nil
 }
 
 func (s *publisher) CloneForFilter() (FilterController, error) {
@@ -105,7 +165,7 @@ func (s *publisher) CloneForFilter() (FilterController, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newFilterPublisher(s.log, sub), nil
+	return newFilterPublisher(s.log, s.env, sub), nil
 }
 
 func (s *publisher) run() {
@@ -122,9 +182,11 @@ loop:
 			}
 			s.distributeEvent(evt)
 		case resultch := <-s.subscribech:
-			resultch <- s.createSubscription()
+			resultch <- s.createSubscription(nil)
+		case request := <-s.subscribefromch:
+			request.resultch <- s.createReplaySubscription(request.version)
 		case sub := <-s.unsubscribech:
-			delete(s.subscriptions, sub)
+			s.unsubscribe(sub)
 		}
 	}
 
@@ -132,7 +194,7 @@ loop:
 		s.log.Debugf("draining: %v subscriptions", len(s.subscriptions))
 		select {
 		case sub := <-s.unsubscribech:
-			delete(s.subscriptions, sub)
+			s.unsubscribe(sub)
 		}
 	}
 
@@ -142,17 +204,40 @@ loop:
 func (s *publisher) distributeEvent(evt Event) {
 	s.log.Debugf("distribute event: sending %v to %v subscriptions", evt, len(s.subscriptions))
 
+	ctx, span := s.env.tracer.Start(EventContext(evt), "kcache.publish",
+		append(eventAttributes(evt), IntAttribute(AttributeSubscribers, len(s.subscriptions)))...)
+
+	if err := s.journal.record(evt); err != nil {
+		s.log.ErrWarn(err, "distribute event: journal")
+	}
+
+	evt = newEvent(ctx, evt.Type(), evt.Resource())
+
 	for sub := range s.subscriptions {
 		sub.send(evt)
 	}
+
+	span.End(nil)
+}
+
+// createReplaySubscription() creates a subscription which first
+// receives the journaled events after version.
+func (s *publisher) createReplaySubscription(version string) subscribeFromResult {
+	backlog, err := s.journal.since(version)
+	if err != nil {
+		return subscribeFromResult{nil, err}
+	}
+	s.log.Debugf("create subscription: replaying %v events after version %v", len(backlog), version)
+	return subscribeFromResult{s.createSubscription(backlog), nil}
 }
 
-func (s *publisher) createSubscription() Subscription {
+func (s *publisher) createSubscription(backlog []Event) Subscription {
 	s.log.Debugf("create subscription: current count %v", len(s.subscriptions))
 
-	sub := newSubscription(s.log, s.lc.ShuttingDown(), s.parent.Ready(), s.parent.Cache())
+	sub := newReplaySubscription(s.log, s.env, s.node, s.lc.ShuttingDown(), s.parent.Ready(), s.parent.Cache(), backlog)
 
 	s.subscriptions[sub] = struct{}{}
+	s.env.metrics.SubscribersChanged(s.kind, 1)
 
 	go func() {
 		select {
@@ -170,8 +255,13 @@ func (s *publisher) createSubscription() Subscription {
 	return sub
 }
 
-func newFilterPublisher(log logutil.Log, subscription FilterSubscription) FilterController {
-	return &filterController{subscription, newPublisher(log, subscription)}
+func (s *publisher) unsubscribe(sub subscription) {
+	delete(s.subscriptions, sub)
+	s.env.metrics.SubscribersChanged(s.kind, -1)
+}
+
+func newFilterPublisher(log logutil.Log, env environment, subscription FilterSubscription) FilterController {
+	return &filterController{subscription, newPublisher(log, env, subscription)}
 }
 
 type filterController struct {
@@ -191,6 +281,10 @@ func (c *filterController) Subscribe() (Subscription, error) {
 	return c.parent.Subscribe()
 }
 
+func (c *filterController) SubscribeFrom(version string) (Subscription, error) {
+	return c.parent.SubscribeFrom(version)
+}
+
 func (c *filterController) SubscribeWithFilter(f filter.Filter) (FilterSubscription, error) {
 	return c.parent.SubscribeWithFilter(f)
 }
@@ -219,6 +313,10 @@ func (c *filterController) Close() {
 	c.parent.Close()
 }
 
+func (c *filterController) CloseWithError(err error) {
+	c.subscription.CloseWithError(err)
+}
+
 func (c *filterController) Error() error {
 	return c.parent.Error()
 }
@@ -226,3 +324,7 @@ func (c *filterController) Error() error {
 func (c *filterController) Refilter(filter filter.Filter) error {
 	return c.subscription.Refilter(filter)
 }
+
+func (c *filterController) Filter() filter.Filter {
+	return c.subscription.Filter()
+}
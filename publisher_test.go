@@ -8,6 +8,7 @@ import (
 	"github.com/boz/kcache/filter"
 	"github.com/boz/kcache/nsname"
 	"github.com/boz/kcache/testutil"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -15,7 +16,7 @@ import (
 func TestPublisher_lifecycle(t *testing.T) {
 	log := logutil.Default()
 	parent, _, readych := testNewSubscription(t, log, filter.Null())
-	publisher := newPublisher(log, parent)
+	publisher := newPublisher(log, defaultEnvironment(), parent)
 	defer parent.Close()
 
 	sub, err := publisher.Subscribe()
@@ -75,7 +76,7 @@ func TestPublisher_lifecycle(t *testing.T) {
 func TestPublisher_Subscribe(t *testing.T) {
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	publisher := newPublisher(log, parent)
+	publisher := newPublisher(log, defaultEnvironment(), parent)
 	defer parent.Close()
 
 	doTestPublisherSubscribe(t, parent, cache, publisher, readych)
@@ -84,7 +85,7 @@ func TestPublisher_Subscribe(t *testing.T) {
 func TestFilterPublisher_Subscribe(t *testing.T) {
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	publisher := newPublisher(log, parent)
+	publisher := newPublisher(log, defaultEnvironment(), parent)
 	defer parent.Close()
 
 	fpublisher, err := publisher.CloneWithFilter(filter.Null())
@@ -113,7 +114,7 @@ func doTestPublisherSubscribe(t *testing.T,
 func TestPublisher_SubscribeWithFilter(t *testing.T) {
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	publisher := newPublisher(log, parent)
+	publisher := newPublisher(log, defaultEnvironment(), parent)
 	defer parent.Close()
 
 	doTestPublisherSubscribeWithFilter(t, parent, cache, publisher, readych)
@@ -122,7 +123,7 @@ func TestPublisher_SubscribeWithFilter(t *testing.T) {
 func TestFilterPublisher_SubscribeWithFilter(t *testing.T) {
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	publisher := newPublisher(log, parent)
+	publisher := newPublisher(log, defaultEnvironment(), parent)
 	defer parent.Close()
 
 	fpublisher, err := publisher.CloneWithFilter(filter.Null())
@@ -151,7 +152,7 @@ func doTestPublisherSubscribeWithFilter(t *testing.T,
 func TestPublisher_SubscribeForFilter(t *testing.T) {
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	publisher := newPublisher(log, parent)
+	publisher := newPublisher(log, defaultEnvironment(), parent)
 	defer parent.Close()
 	doTestPublisherSubscribeForFilter(t, parent, cache, publisher, readych)
 }
@@ -159,7 +160,7 @@ func TestPublisher_SubscribeForFilter(t *testing.T) {
 func TestFilterPublisher_SubscribeForFilter(t *testing.T) {
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	publisher := newPublisher(log, parent)
+	publisher := newPublisher(log, defaultEnvironment(), parent)
 	defer parent.Close()
 
 	fpublisher, err := publisher.CloneWithFilter(filter.Null())
@@ -192,7 +193,7 @@ func doTestPublisherSubscribeForFilter(t *testing.T,
 func TestPublisher_Clone(t *testing.T) {
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	publisher := newPublisher(log, parent)
+	publisher := newPublisher(log, defaultEnvironment(), parent)
 	defer parent.Close()
 
 	doTestPublisherClone(t, parent, cache, publisher, readych)
@@ -201,7 +202,7 @@ func TestPublisher_Clone(t *testing.T) {
 func TestFilterPublisher_Clone(t *testing.T) {
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	publisher := newPublisher(log, parent)
+	publisher := newPublisher(log, defaultEnvironment(), parent)
 	defer parent.Close()
 
 	fpublisher, err := publisher.CloneWithFilter(filter.Null())
@@ -231,7 +232,7 @@ func doTestPublisherClone(t *testing.T,
 func TestPublisher_CloneWithFilter(t *testing.T) {
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	publisher := newPublisher(log, parent)
+	publisher := newPublisher(log, defaultEnvironment(), parent)
 	defer parent.Close()
 
 	doTestPublisherCloneWithFilter(t, parent, cache, publisher, readych)
@@ -240,7 +241,7 @@ func TestPublisher_CloneWithFilter(t *testing.T) {
 func TestFilterPublisher_CloneWithFilter(t *testing.T) {
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	publisher := newPublisher(log, parent)
+	publisher := newPublisher(log, defaultEnvironment(), parent)
 	defer parent.Close()
 
 	fpublisher, err := publisher.CloneWithFilter(filter.Null())
@@ -270,7 +271,7 @@ func doTestPublisherCloneWithFilter(t *testing.T,
 func TestPublisher_CloneForFilter(t *testing.T) {
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	publisher := newPublisher(log, parent)
+	publisher := newPublisher(log, defaultEnvironment(), parent)
 	defer parent.Close()
 
 	doTestPublisherCloneForFilter(t, parent, cache, publisher, readych)
@@ -279,7 +280,7 @@ func TestPublisher_CloneForFilter(t *testing.T) {
 func TestFilterPublisher_CloneForFilter(t *testing.T) {
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	publisher := newPublisher(log, parent)
+	publisher := newPublisher(log, defaultEnvironment(), parent)
 	defer parent.Close()
 
 	fpublisher, err := publisher.CloneWithFilter(filter.Null())
@@ -288,6 +289,29 @@ func TestFilterPublisher_CloneForFilter(t *testing.T) {
 	doTestPublisherCloneForFilter(t, parent, cache, fpublisher, readych)
 }
 
+func TestFilterPublisher_CloseWithError(t *testing.T) {
+	log := logutil.Default()
+	parent, _, readych := testNewSubscription(t, log, filter.Null())
+	publisher := newPublisher(log, defaultEnvironment(), parent)
+	defer parent.Close()
+
+	close(readych)
+
+	fpublisher, err := publisher.CloneForFilter()
+	require.NoError(t, err)
+
+	clone, err := fpublisher.Clone()
+	require.NoError(t, err)
+
+	fpublisher.CloseWithError(errors.New("boom"))
+
+	testutil.AssertDone(t, "fpublisher", fpublisher)
+	testutil.AssertDone(t, "clone", clone)
+	testutil.AssertNotDone(t, "publisher", publisher)
+
+	assert.EqualError(t, fpublisher.Error(), "boom")
+}
+
 func doTestPublisherCloneForFilter(t *testing.T,
 	parent subscription, cache cache, publisher Controller, readych chan struct{}) {
 
@@ -0,0 +1,97 @@
+package kcache
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultSnapshotPeriod = 5 * time.Minute
+)
+
+// snapshot is the file format of a cache snapshot.
+type snapshot struct {
+	ResourceVersion string            `json:"resourceVersion"`
+	Items           []json.RawMessage `json:"items"`
+}
+
+// warmStart holds a snapshot read at startup.
+type warmStart struct {
+	version  string
+	objs     []metav1.Object
+	resource string
+}
+
+type snapshotter struct {
+	path      string
+	prototype reflect.Type
+	period    time.Duration
+}
+
+// write() atomically replaces the snapshot file with objs at version.
+func (s *snapshotter) write(version string, objs []metav1.Object) error {
+	snap := snapshot{ResourceVersion: version, Items: make([]json.RawMessage, 0, len(objs))}
+	for _, obj := range objs {
+		buf, err := json.Marshal(obj)
+		if err != nil {
+			return errors.Wrap(err, "snapshot: encode")
+		}
+		snap.Items = append(snap.Items, buf)
+	}
+
+	buf, err := json.Marshal(snap)
+	if err != nil {
+		return errors.Wrap(err, "snapshot: encode")
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return errors.Wrap(err, "snapshot: create")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "snapshot: write")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "snapshot: write")
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), s.path), "snapshot: rename")
+}
+
+// read() returns the version and objects of the snapshot file.
+func (s *snapshotter) read() (string, []metav1.Object, error) {
+	buf, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "snapshot: read")
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(buf, &snap); err != nil {
+		return "", nil, errors.Wrap(err, "snapshot: decode")
+	}
+
+	if snap.ResourceVersion == "" {
+		return "", nil, errors.New("snapshot: missing resource version")
+	}
+
+	objs := make([]metav1.Object, 0, len(snap.Items))
+	for _, item := range snap.Items {
+		obj := reflect.New(s.prototype).Interface()
+		if err := json.Unmarshal(item, obj); err != nil {
+			return "", nil, errors.Wrap(err, "snapshot: decode")
+		}
+		objs = append(objs, obj.(metav1.Object))
+	}
+
+	return snap.ResourceVersion, objs, nil
+}
@@ -0,0 +1,142 @@
+package kcache
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/boz/kcache/client/mocks"
+	"github.com/boz/kcache/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestSnapshotter(t *testing.T) {
+	s := &snapshotter{
+		path:      filepath.Join(t.TempDir(), "pods.json"),
+		prototype: reflect.TypeOf(v1.Pod{}),
+	}
+
+	_, _, err := s.read()
+	assert.Error(t, err)
+
+	require.NoError(t, s.write("5", []metav1.Object{testGenPod("ns", "a", "1"), testGenPod("ns", "b", "2")}))
+
+	version, objs, err := s.read()
+	require.NoError(t, err)
+	assert.Equal(t, "5", version)
+	require.Len(t, objs, 2)
+	require.IsType(t, &v1.Pod{}, objs[0])
+	assert.Equal(t, "a", objs[0].GetName())
+	assert.Equal(t, "2", objs[1].GetResourceVersion())
+
+	require.NoError(t, s.write("6", nil))
+	version, objs, err = s.read()
+	require.NoError(t, err)
+	assert.Equal(t, "6", version)
+	assert.Empty(t, objs)
+
+	builder := NewBuilder()
+	builder.Snapshot().Path(s.path).Object(nil)
+	_, err = builder.Create()
+	assert.Error(t, err)
+}
+
+func TestController_warmStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "pods.json")
+	s := &snapshotter{path: path, prototype: reflect.TypeOf(v1.Pod{})}
+	require.NoError(t, s.write("5", []metav1.Object{testGenPod("ns", "a", "1"), testGenPod("ns", "b", "2")}))
+
+	eventch := make(chan watch.Event, 10)
+	listch := make(chan time.Time, 1)
+
+	mwatch := &mocks.WatchInterface{}
+	mwatch.On("ResultChan").Return(eventch)
+	mwatch.On("Stop").Return()
+
+	client := &mocks.Client{}
+	versions := make(chan string, 10)
+	client.On("Watch", mock.Anything, mock.MatchedBy(func(opts metav1.ListOptions) bool {
+		select {
+		case versions <- opts.ResourceVersion:
+		default:
+		}
+		return true
+	})).Return(mwatch, nil)
+	client.On("List", mock.Anything, mock.AnythingOfType("v1.ListOptions")).
+		WaitUntil(listch).
+		Return(&v1.PodList{
+			ListMeta: metav1.ListMeta{ResourceVersion: "7"},
+			Items:    []v1.Pod{*testGenPod("ns", "a", "1"), *testGenPod("ns", "c", "6")},
+		}, nil)
+
+	builder := NewBuilder().
+		Context(ctx).
+		Client(client)
+	builder.Snapshot().Path(path).Object(&v1.Pod{}).Period(0)
+
+	controller, err := builder.Create()
+	require.NoError(t, err)
+
+	// ready from the snapshot, without listing.
+	testutil.AssertReady(t, "controller", controller)
+	assert.Equal(t, []string{"a", "b"}, testCacheNames(t, controller.Cache()))
+
+	sub, err := controller.Subscribe()
+	require.NoError(t, err)
+	testutil.AssertReady(t, "sub", sub)
+
+	select {
+	case version := <-versions:
+		assert.Equal(t, "5", version)
+	case <-testutil.AsyncWaitch(ctx):
+		require.Fail(t, "no watch")
+	}
+
+	// an expired version falls back to a full list.
+	eventch <- watch.Event{Type: watch.Error, Object: &metav1.Status{Code: http.StatusGone}}
+	listch <- time.Now()
+
+	var events []string
+	for len(events) < 2 {
+		select {
+		case evt := <-sub.Events():
+			events = append(events, string(evt.Type())+" "+evt.Resource().GetName())
+		case <-testutil.AsyncWaitch(ctx):
+			require.Fail(t, "missing events", "%v", events)
+		}
+	}
+	sort.Strings(events)
+	assert.Equal(t, []string{"create c", "delete b"}, events)
+
+	controller.Close()
+	testutil.AssertDone(t, "controller", controller)
+
+	version, objs, err := s.read()
+	require.NoError(t, err)
+	assert.Equal(t, "7", version)
+	assert.Len(t, objs, 2)
+}
+
+func testCacheNames(t *testing.T, cache CacheReader) []string {
+	objs, err := cache.List()
+	require.NoError(t, err)
+	var names []string
+	for _, obj := range objs {
+		names = append(names, obj.GetName())
+	}
+	sort.Strings(names)
+	return names
+}
@@ -31,23 +31,39 @@ type _subscription struct {
 
 	cache CacheReader
 
+	env  environment
+	node *topologyNode
+
 	log logutil.Log
 	lc  lifecycle.Lifecycle
 }
 
-func newSubscription(log logutil.Log, stopch <-chan struct{}, readych <-chan struct{}, cache CacheReader) subscription {
+func newSubscription(log logutil.Log, env environment, parent *topologyNode, stopch <-chan struct{}, readych <-chan struct{}, cache CacheReader) subscription {
+	return newReplaySubscription(log, env, parent, stopch, readych, cache, nil)
+}
+
+// newReplaySubscription() creates a subscription whose first
+// events are backlog.
+func newReplaySubscription(log logutil.Log, env environment, parent *topologyNode, stopch <-chan struct{}, readych <-chan struct{}, cache CacheReader, backlog []Event) subscription {
 	log = log.WithComponent("subscription")
 
 	lc := lifecycle.New()
 	s := &_subscription{
 		readych: readych,
 		inch:    make(chan Event),
-		outch:   make(chan Event, EventBufsiz),
+		outch:   make(chan Event, EventBufsiz+len(backlog)),
 		cache:   cache,
+		env:     env,
 		log:     log,
 		lc:      lc,
 	}
 
+	s.node = parent.add("subscription", s)
+
+	for _, evt := range backlog {
+		s.outch <- evt
+	}
+
 	go s.lc.WatchChannel(stopch)
 
 	go s.run()
@@ -78,6 +94,14 @@ func (s *_subscription) Error() error {
 	return s.lc.Error()
 }
 
+func (s *_subscription) topologyNode() *topologyNode {
+	return s.node
+}
+
+func (s *_subscription) bufferDepth() (int, int) {
+	return len(s.outch), cap(s.outch)
+}
+
 func (s *_subscription) send(ev Event) error {
 	select {
 	case s.inch <- ev:
@@ -100,7 +124,9 @@ func (s *_subscription) run() {
 		case evt := <-s.inch:
 			select {
 			case s.outch <- evt:
+				s.env.metrics.BufferFill(len(s.outch), cap(s.outch))
 			default:
+				s.env.metrics.EventDropped()
 				s.log.Warnf("event buffer overrun")
 			}
 		}
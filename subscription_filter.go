@@ -2,6 +2,7 @@ package kcache
 
 import (
 	"context"
+	"sync"
 
 	lifecycle "github.com/boz/go-lifecycle"
 	logutil "github.com/boz/go-logutil"
@@ -12,6 +13,12 @@ import (
 type FilterSubscription interface {
 	Subscription
 	Refilter(filter.Filter) error
+
+	// Filter() returns the filter currently applied.
+	Filter() filter.Filter
+
+	// CloseWithError() closes the subscription and sets its Error().
+	CloseWithError(error)
 }
 
 type filterSubscription struct {
@@ -23,14 +30,18 @@ type filterSubscription struct {
 	outch   chan Event
 	readych chan struct{}
 
-	filter filter.Filter
-	cache  cache
+	filter    filter.Filter
+	filterMtx sync.RWMutex
+	cache     cache
+
+	env  environment
+	node *topologyNode
 
 	lc  lifecycle.Lifecycle
 	log logutil.Log
 }
 
-func newFilterSubscription(log logutil.Log, parent Subscription, f filter.Filter, deferReady bool) FilterSubscription {
+func newFilterSubscription(log logutil.Log, env environment, parent Subscription, f filter.Filter, deferReady bool) FilterSubscription {
 
 	ctx := context.Background()
 	lc := lifecycle.New()
@@ -42,11 +53,14 @@ func newFilterSubscription(log logutil.Log, parent Subscription, f filter.Filter
 		readych:    make(chan struct{}),
 		deferReady: deferReady,
 		filter:     f,
-		cache:      newCache(ctx, log, lc.ShuttingDown(), f),
+		cache:      newCache(ctx, log, env, "filter-subscription", lc.ShuttingDown(), f),
+		env:        env,
 		lc:         lc,
 		log:        log,
 	}
 
+	s.node = nodeOf(parent).add("filter-subscription", s)
+
 	go s.run()
 
 	return s
@@ -64,6 +78,9 @@ func (s *filterSubscription) Events() <-chan Event {
 func (s *filterSubscription) Close() {
 	s.parent.Close()
 }
+func (s *filterSubscription) CloseWithError(err error) {
+	s.lc.ShutdownAsync(err)
+}
 func (s *filterSubscription) Done() <-chan struct{} {
 	return s.lc.Done()
 }
@@ -74,6 +91,14 @@ func (s *filterSubscription) Error() error {
 	return s.parent.Error()
 }
 
+func (s *filterSubscription) topologyNode() *topologyNode {
+	return s.node
+}
+
+func (s *filterSubscription) bufferDepth() (int, int) {
+	return len(s.outch), cap(s.outch)
+}
+
 func (s *filterSubscription) Refilter(filter filter.Filter) error {
 	select {
 	case s.refilterch <- filter:
@@ -83,6 +108,18 @@ func (s *filterSubscription) Refilter(filter filter.Filter) error {
 	}
 }
 
+func (s *filterSubscription) Filter() filter.Filter {
+	s.filterMtx.RLock()
+	defer s.filterMtx.RUnlock()
+	return s.filter
+}
+
+func (s *filterSubscription) setFilter(f filter.Filter) {
+	s.filterMtx.Lock()
+	defer s.filterMtx.Unlock()
+	s.filter = f
+}
+
 func (s *filterSubscription) run() {
 	defer s.lc.ShutdownCompleted()
 
@@ -108,14 +145,14 @@ loop:
 				continue
 			}
 
-			list, err := s.parent.Cache().List()
+			snap, err := s.parent.Cache().Snapshot()
 			if err != nil {
-				s.log.Debugf("parent ready: cache list error: %v", err)
-				s.lc.ShutdownInitiated(errors.Wrap(err, "parent ready: cache list"))
+				s.log.Debugf("parent ready: cache snapshot error: %v", err)
+				s.lc.ShutdownInitiated(errors.Wrap(err, "parent ready: cache snapshot"))
 				break loop
 			}
 
-			if _, err := s.cache.sync(list); err != nil {
+			if _, err := s.cache.sync(snap.ResourceVersion(), snap.List()); err != nil {
 				s.log.Debugf("parent ready: cache sync error: %v", err)
 				s.lc.ShutdownInitiated(errors.Wrap(err, "parent ready: cache sync"))
 				break loop
@@ -138,13 +175,13 @@ loop:
 				continue
 
 			case preadych != nil && isNew:
-				if _, err := s.cache.refilter(nil, f); err != nil {
+				if _, err := s.cache.refilter("", nil, f); err != nil {
 					s.log.Debugf("refilter: cache refilter (not ready): %v", err)
 					s.lc.ShutdownInitiated(errors.Wrap(err, "refilter: cache refilter (not ready)"))
 					break loop
 				}
 				s.log.Debugf("refilter: deferring ready (filter changed)")
-				s.filter = f
+				s.setFilter(f)
 				pending = true
 				continue
 
@@ -162,20 +199,20 @@ loop:
 
 			// pready == nil && isNew
 
-			list, err := s.parent.Cache().List()
+			snap, err := s.parent.Cache().Snapshot()
 			if err != nil {
-				s.log.Debugf("refilter: cache list error: %v", err)
-				s.lc.ShutdownInitiated(errors.Wrap(err, "refilter: cache list"))
+				s.log.Debugf("refilter: cache snapshot error: %v", err)
+				s.lc.ShutdownInitiated(errors.Wrap(err, "refilter: cache snapshot"))
 				break loop
 			}
 
-			events, err := s.cache.refilter(list, f)
+			events, err := s.cache.refilter(snap.ResourceVersion(), snap.List(), f)
 			if err != nil {
 				s.log.Debugf("refilter: cache refilter error: %v", err)
 				s.lc.ShutdownInitiated(errors.Wrap(err, "refilter: cache refilter"))
 				break loop
 			}
-			s.filter = f
+			s.setFilter(f)
 
 			if !ready {
 				s.log.Debugf("refilter: making ready (filter changed)")
@@ -186,7 +223,7 @@ loop:
 
 			s.log.Debugf("refilter: %v events", len(events))
 
-			s.distributeEvents(events)
+			s.distributeEvents(context.Background(), events)
 
 		case evt, ok := <-s.parent.Events():
 
@@ -208,7 +245,7 @@ loop:
 
 			s.log.Debugf("update: %v events", len(events))
 
-			s.distributeEvents(events)
+			s.distributeEvents(EventContext(evt), events)
 
 		}
 	}
@@ -220,12 +257,21 @@ loop:
 	<-s.parent.Done()
 }
 
-func (s *filterSubscription) distributeEvents(events []Event) {
+func (s *filterSubscription) distributeEvents(ctx context.Context, events []Event) {
+	_, span := s.env.tracer.Start(ctx, "kcache.filter.distribute",
+		IntAttribute(AttributeEvents, len(events)))
+
+	var err error
 	for _, evt := range events {
 		select {
 		case s.outch <- evt:
+			s.env.metrics.BufferFill(len(s.outch), cap(s.outch))
 		default:
+			s.env.metrics.EventDropped()
 			s.log.Warnf("event buffer overrun")
+			err = errEventDropped
 		}
 	}
+
+	span.End(err)
 }
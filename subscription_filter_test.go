@@ -18,7 +18,7 @@ func TestFilterSubscriptionReady_immediate(t *testing.T) {
 
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	sub := newFilterSubscription(log, parent, filter.Null(), false)
+	sub := newFilterSubscription(log, defaultEnvironment(), parent, filter.Null(), false)
 	defer parent.Close()
 
 	testDoFilterSubscriptionReady(t, "immediate", parent, sub, cache)
@@ -50,7 +50,7 @@ func TestFilterSubscriptionReady_deferred(t *testing.T) {
 
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	sub := newFilterSubscription(log, parent, filter.Null(), true)
+	sub := newFilterSubscription(log, defaultEnvironment(), parent, filter.Null(), true)
 	defer parent.Close()
 
 	testDoFilterSubscriptionReady(t, "deferred", parent, sub, cache)
@@ -82,7 +82,7 @@ func TestFilterSubscriptionRefilter_immediate_refilter_before_ready(t *testing.T
 
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	sub := newFilterSubscription(log, parent, filter.Null(), false)
+	sub := newFilterSubscription(log, defaultEnvironment(), parent, filter.Null(), false)
 	defer parent.Close()
 
 	cache.update(testGenEvent(EventTypeCreate, "a", "b", "1"))
@@ -167,7 +167,7 @@ func TestFilterSubscriptionRefilter_immediate_refilter_after_ready(t *testing.T)
 
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	sub := newFilterSubscription(log, parent, filter.Null(), false)
+	sub := newFilterSubscription(log, defaultEnvironment(), parent, filter.Null(), false)
 	defer parent.Close()
 
 	cache.update(testGenEvent(EventTypeCreate, "a", "b", "1"))
@@ -225,7 +225,7 @@ func TestFilterSubscriptionRefilter_deferred_refilter_before_ready(t *testing.T)
 
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	sub := newFilterSubscription(log, parent, filter.Null(), true)
+	sub := newFilterSubscription(log, defaultEnvironment(), parent, filter.Null(), true)
 	defer parent.Close()
 
 	cache.update(testGenEvent(EventTypeCreate, "a", "b", "1"))
@@ -282,7 +282,7 @@ func TestFilterSubscriptionRefilter_deferred_refilter_after_ready(t *testing.T)
 
 	log := logutil.Default()
 	parent, cache, readych := testNewSubscription(t, log, filter.Null())
-	sub := newFilterSubscription(log, parent, filter.Null(), true)
+	sub := newFilterSubscription(log, defaultEnvironment(), parent, filter.Null(), true)
 	defer parent.Close()
 
 	cache.update(testGenEvent(EventTypeCreate, "a", "b", "1"))
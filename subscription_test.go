@@ -22,9 +22,9 @@ func testDoTestSubscription(t *testing.T, name string, stopfn func(subscription,
 
 	readych := make(chan struct{})
 	stopch := make(chan struct{})
-	cache := newCache(ctx, log, stopch, filter.Null())
+	cache := newCache(ctx, log, defaultEnvironment(), "controller", stopch, filter.Null())
 
-	sub := newSubscription(log, stopch, readych, cache)
+	sub := newSubscription(log, defaultEnvironment(), nil, stopch, readych, cache)
 	defer sub.Close()
 
 	testutil.AssertNotDone(t, name, sub)
@@ -29,9 +29,9 @@ func testNewSubscription(t *testing.T, log logutil.Log, f filter.Filter) (subscr
 
 	ctx, cancel := context.WithCancel(context.Background())
 	readych := make(chan struct{})
-	cache := newCache(ctx, log, nil, f)
+	cache := newCache(ctx, log, defaultEnvironment(), "controller", nil, f)
 
-	sub := newSubscription(log, nil, readych, cache)
+	sub := newSubscription(log, defaultEnvironment(), nil, nil, readych, cache)
 
 	go func() {
 		<-sub.Done()
@@ -1,8 +1,9 @@
 package kcache
 
 import (
-	"math/rand"
 	"time"
+
+	"github.com/boz/kcache/clock"
 )
 
 type ticker interface {
@@ -12,9 +13,10 @@ type ticker interface {
 	Done() <-chan struct{}
 }
 
-func newTicker(period time.Duration, fuzz float64) ticker {
+func newTicker(clock clock.Clock, period time.Duration, fuzz float64) ticker {
 
 	t := &_ticker{
+		clock:   clock,
 		period:  period,
 		fuzz:    fuzz,
 		nextch:  make(chan int),
@@ -29,6 +31,7 @@ func newTicker(period time.Duration, fuzz float64) ticker {
 }
 
 type _ticker struct {
+	clock  clock.Clock
 	period time.Duration
 	fuzz   float64
 
@@ -64,7 +67,7 @@ func (t *_ticker) run() {
 	defer close(t.donech)
 
 	count := 0
-	timer := time.NewTimer(t.nextPeriod())
+	timer := t.clock.NewTimer(t.nextPeriod())
 
 	var nextch chan int
 
@@ -73,8 +76,9 @@ func (t *_ticker) run() {
 		select {
 
 		case <-t.resetch:
-			if !timer.Stop() {
-				<-timer.C
+			// the timer has already been received from if nextch is set.
+			if !timer.Stop() && nextch == nil {
+				<-timer.C()
 			}
 			timer.Reset(t.nextPeriod())
 			nextch = nil
@@ -83,8 +87,7 @@ func (t *_ticker) run() {
 			timer.Stop()
 			return
 
-		case <-timer.C:
-			timer.Stop()
+		case <-timer.C():
 			nextch = t.nextch
 
 		case nextch <- count:
@@ -102,7 +105,7 @@ func (t *_ticker) nextPeriod() time.Duration {
 	min := float64(t.period) - delta
 	max := float64(t.period) + delta
 
-	r := rand.Float64()
+	r := t.clock.Float64()
 
 	return time.Duration(min + r*(max-min+1))
 
@@ -0,0 +1,68 @@
+package kcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boz/kcache/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicker(t *testing.T) {
+	clk := clock.NewFake(time.Now())
+
+	ticker := newTicker(clk, 10*time.Second, 0.1)
+	defer ticker.Stop()
+
+	clk.BlockUntil(1)
+
+	clk.Advance(9 * time.Second)
+	testAssertNoTick(t, ticker)
+
+	clk.Advance(time.Second)
+	assert.Equal(t, 0, testNextTick(t, ticker))
+
+	// a tick that was due is discarded by a reset.
+	clk.BlockUntil(1)
+	clk.Advance(10 * time.Second)
+	ticker.Reset()
+	testAssertNoTick(t, ticker)
+
+	clk.Advance(10 * time.Second)
+	assert.Equal(t, 1, testNextTick(t, ticker))
+
+	// jitter comes from the clock.
+	clk.SetRandom(0)
+	clk.BlockUntil(1)
+	clk.Advance(10 * time.Second)
+	ticker.Reset()
+
+	clk.Advance(9 * time.Second)
+	assert.Equal(t, 2, testNextTick(t, ticker))
+
+	ticker.Stop()
+	select {
+	case <-ticker.Done():
+	case <-time.After(time.Second):
+		require.Fail(t, "ticker not done")
+	}
+}
+
+func testNextTick(t *testing.T, ticker ticker) int {
+	select {
+	case count := <-ticker.Next():
+		return count
+	case <-time.After(time.Second):
+		require.Fail(t, "no tick")
+		return -1
+	}
+}
+
+func testAssertNoTick(t *testing.T, ticker ticker) {
+	select {
+	case <-ticker.Next():
+		assert.Fail(t, "unexpected tick")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
@@ -0,0 +1,155 @@
+package kcache
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	lifecycle "github.com/boz/go-lifecycle"
+)
+
+// Topology records the tree of controllers, publishers and subscriptions
+// built from the controllers created with it.  Nodes are removed once
+// they are done.
+type Topology struct {
+	nodes  map[uint64]*topologyNode
+	nextID uint64
+	mtx    sync.Mutex
+}
+
+func NewTopology() *Topology {
+	return &Topology{nodes: make(map[uint64]*topologyNode)}
+}
+
+// TopologyNode describes the current state of a registered node.
+type TopologyNode struct {
+	ID     uint64 `json:"id"`
+	Parent uint64 `json:"parent,omitempty"`
+	Kind   string `json:"kind"`
+
+	Filter string `json:"filter,omitempty"`
+	Ready  bool   `json:"ready"`
+	Error  string `json:"error,omitempty"`
+
+	// BufferDepth is the number of events waiting in the node's buffer.
+	BufferDepth int `json:"bufferDepth"`
+	BufferSize  int `json:"bufferSize"`
+}
+
+// Nodes() returns all registered nodes, ordered by ID.
+// A node's parent always precedes it.
+func (t *Topology) Nodes() []TopologyNode {
+	t.mtx.Lock()
+	nodes := make([]*topologyNode, 0, len(t.nodes))
+	for _, node := range t.nodes {
+		nodes = append(nodes, node)
+	}
+	t.mtx.Unlock()
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].id < nodes[j].id })
+
+	result := make([]TopologyNode, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, node.describe())
+	}
+	return result
+}
+
+// Cache() returns the cache of the node with the given ID.
+func (t *Topology) Cache(id uint64) (CacheReader, bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	node, ok := t.nodes[id]
+	if !ok {
+		return nil, false
+	}
+	return node.target.Cache(), true
+}
+
+type topologyTarget interface {
+	CacheController
+	Done() <-chan struct{}
+	Error() error
+}
+
+// buffered is implemented by nodes with an event buffer.
+type buffered interface {
+	bufferDepth() (int, int)
+}
+
+type topologyNode struct {
+	topology *Topology
+	id       uint64
+	parent   *topologyNode
+	kind     string
+	target   topologyTarget
+}
+
+// add() registers target under parent.  A nil topology
+// registers nothing and returns a nil node.
+func (t *Topology) add(parent *topologyNode, kind string, target topologyTarget) *topologyNode {
+	if t == nil {
+		return nil
+	}
+
+	t.mtx.Lock()
+	t.nextID++
+	node := &topologyNode{t, t.nextID, parent, kind, target}
+	t.nodes[node.id] = node
+	t.mtx.Unlock()
+
+	go func() {
+		<-target.Done()
+		t.mtx.Lock()
+		delete(t.nodes, node.id)
+		t.mtx.Unlock()
+	}()
+
+	return node
+}
+
+// add() registers target as a child of n.
+func (n *topologyNode) add(kind string, target topologyTarget) *topologyNode {
+	if n == nil {
+		return nil
+	}
+	return n.topology.add(n, kind, target)
+}
+
+func (n *topologyNode) describe() TopologyNode {
+	info := TopologyNode{ID: n.id, Kind: n.kind}
+
+	if n.parent != nil {
+		info.Parent = n.parent.id
+	}
+
+	select {
+	case <-n.target.Ready():
+		info.Ready = true
+	default:
+	}
+
+	if err := n.target.Error(); err != nil && err != lifecycle.ErrRunning {
+		info.Error = err.Error()
+	}
+
+	if f, ok := n.target.(Filtered); ok {
+		if f := f.Filter(); f != nil {
+			info.Filter = fmt.Sprint(f)
+		}
+	}
+
+	if b, ok := n.target.(buffered); ok {
+		info.BufferDepth, info.BufferSize = b.bufferDepth()
+	}
+
+	return info
+}
+
+// nodeOf() returns the node of a parent built by this package.
+func nodeOf(parent interface{}) *topologyNode {
+	if p, ok := parent.(interface{ topologyNode() *topologyNode }); ok {
+		return p.topologyNode()
+	}
+	return nil
+}
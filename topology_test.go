@@ -0,0 +1,99 @@
+package kcache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/boz/kcache/client/mocks"
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	"github.com/boz/kcache/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestTopology(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mwatch := &mocks.WatchInterface{}
+	mwatch.On("ResultChan").Return(make(chan watch.Event))
+	mwatch.On("Stop").Return()
+
+	list := &v1.PodList{
+		ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+		Items:    []v1.Pod{*testGenPod("ns", "a", "1"), *testGenPod("ns", "b", "2")},
+	}
+
+	client := &mocks.Client{}
+	client.On("Watch", mock.Anything, mock.AnythingOfType("v1.ListOptions")).Return(mwatch, nil)
+	client.On("List", mock.Anything, mock.AnythingOfType("v1.ListOptions")).Return(list, nil)
+
+	topology := NewTopology()
+
+	controller, err := NewBuilder().
+		Context(ctx).
+		Client(client).
+		Topology(topology).
+		Create()
+	require.NoError(t, err)
+
+	testutil.AssertReady(t, "controller", controller)
+
+	clone, err := controller.CloneWithFilter(filter.NSName(nsname.New("ns", "a")))
+	require.NoError(t, err)
+	testutil.AssertReady(t, "clone", clone)
+
+	nodes := topology.Nodes()
+
+	kinds := make([]string, 0, len(nodes))
+	byID := make(map[uint64]TopologyNode)
+	for _, node := range nodes {
+		kinds = append(kinds, node.Kind)
+		byID[node.ID] = node
+		assert.True(t, node.Ready, node.Kind)
+		assert.Empty(t, node.Error, node.Kind)
+	}
+
+	// controller -> subscription -> publisher -> subscription -> filter-subscription -> publisher
+	require.Equal(t, []string{"controller", "subscription", "publisher", "subscription", "filter-subscription", "publisher"}, kinds)
+	for _, node := range nodes[1:] {
+		_, ok := byID[node.Parent]
+		assert.True(t, ok, node.Kind)
+	}
+	assert.Equal(t, uint64(0), nodes[0].Parent)
+
+	fsub := nodes[4]
+	assert.Equal(t, "nsname(ns/a)", fsub.Filter)
+	assert.Equal(t, EventBufsiz, fsub.BufferSize)
+
+	cache, ok := topology.Cache(fsub.ID)
+	require.True(t, ok)
+	objs, err := cache.List()
+	require.NoError(t, err)
+	assert.Len(t, objs, 1)
+
+	cache, ok = topology.Cache(nodes[0].ID)
+	require.True(t, ok)
+	objs, err = cache.List()
+	require.NoError(t, err)
+	assert.Len(t, objs, 2)
+
+	// done nodes are removed.
+	clone.CloseWithError(errors.New("boom"))
+	testutil.AssertDone(t, "clone", clone)
+
+	assert.Eventually(t, func() bool {
+		return len(topology.Nodes()) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	_, ok = topology.Cache(fsub.ID)
+	assert.False(t, ok)
+}
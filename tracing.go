@@ -0,0 +1,117 @@
+package kcache
+
+import (
+	"context"
+	builtin_errors "errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/boz/kcache/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	errEventDropped = builtin_errors.New("event dropped: buffer full")
+)
+
+// Span attribute keys.
+const (
+	AttributeResource        = "kcache.resource"
+	AttributeNamespace       = "kcache.namespace"
+	AttributeName            = "kcache.name"
+	AttributeResourceVersion = "kcache.resource_version"
+	AttributeEventType       = "kcache.event_type"
+	AttributeObjects         = "kcache.objects"
+	AttributeEvents          = "kcache.events"
+	AttributeSubscribers     = "kcache.subscribers"
+)
+
+// Tracer starts spans around list requests, watch sessions,
+// cache updates and event delivery.
+//
+// Implementations must be safe for concurrent use.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttributes(...Attribute)
+
+	// End() completes the span, recording err if it is not nil.
+	End(err error)
+}
+
+// Attribute is a span attribute.  Value is a string, int or bool.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+func StringAttribute(key, value string) Attribute {
+	return Attribute{key, value}
+}
+
+func IntAttribute(key string, value int) Attribute {
+	return Attribute{key, value}
+}
+
+// NullTracer() returns a Tracer which records nothing.
+func NullTracer() Tracer {
+	return nullTracer{}
+}
+
+type nullTracer struct{}
+
+func (nullTracer) Start(ctx context.Context, _ string, _ ...Attribute) (context.Context, Span) {
+	return ctx, nullSpan{}
+}
+
+type nullSpan struct{}
+
+func (nullSpan) SetAttributes(...Attribute) {}
+func (nullSpan) End(error)                  {}
+
+// objectAttributes() describes obj for a span.
+func objectAttributes(obj metav1.Object) []Attribute {
+	return []Attribute{
+		StringAttribute(AttributeResource, resourceName(obj)),
+		StringAttribute(AttributeNamespace, obj.GetNamespace()),
+		StringAttribute(AttributeName, obj.GetName()),
+		StringAttribute(AttributeResourceVersion, obj.GetResourceVersion()),
+	}
+}
+
+func eventAttributes(evt Event) []Attribute {
+	return append(objectAttributes(evt.Resource()),
+		StringAttribute(AttributeEventType, string(evt.Type())))
+}
+
+// clientNamespace() returns the namespace c is limited to, if known.
+func clientNamespace(c interface{}) string {
+	if c, ok := c.(client.Scoped); ok {
+		return c.Namespace()
+	}
+	return ""
+}
+
+// resourceName() returns the kind of obj, or of the items of a list.
+func resourceName(obj interface{}) string {
+	t := reflect.TypeOf(obj)
+	if t == nil {
+		return ""
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return strings.TrimSuffix(t.Name(), "List")
+}
+
+// detached carries the values of its parent but is never canceled.
+type detached struct {
+	context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detached) Done() <-chan struct{}       { return nil }
+func (detached) Err() error                  { return nil }
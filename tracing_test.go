@@ -0,0 +1,188 @@
+package kcache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/boz/kcache/client/mocks"
+	"github.com/boz/kcache/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestController_tracing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventch := make(chan watch.Event, 10)
+
+	mwatch := &mocks.WatchInterface{}
+	mwatch.On("ResultChan").Return(eventch)
+	mwatch.On("Stop").Return()
+
+	list := &v1.PodList{
+		ListMeta: metav1.ListMeta{ResourceVersion: "5"},
+		Items:    []v1.Pod{*testGenPod("ns", "a", "1")},
+	}
+
+	client := &mocks.Client{}
+	client.On("Watch", mock.Anything, mock.AnythingOfType("v1.ListOptions")).Return(mwatch, nil)
+	client.On("List", mock.Anything, mock.AnythingOfType("v1.ListOptions")).Return(list, nil)
+
+	tracer := &testTracer{}
+
+	controller, err := NewBuilder().
+		Context(ctx).
+		Client(testScopedClient{client, "ns"}).
+		Tracer(tracer).
+		Create()
+	require.NoError(t, err)
+
+	testutil.AssertReady(t, "controller", controller)
+
+	sub, err := controller.Subscribe()
+	require.NoError(t, err)
+
+	eventch <- watch.Event{Type: watch.Added, Object: testGenPod("ns", "b", "6")}
+
+	var evt Event
+	select {
+	case evt = <-sub.Events():
+	case <-testutil.AsyncWaitch(ctx):
+		require.Fail(t, "no event")
+	}
+
+	// the delivered event carries the span of its publication,
+	// which descends from the watch event.
+	span := EventContext(evt).Value(testSpanKey{}).(*testSpan)
+	assert.Equal(t, "kcache.publish", span.name)
+	assert.Equal(t, []string{"kcache.publish", "kcache.cache.update", "kcache.watch.event", "kcache.watch.session"}, span.lineage())
+	assert.Equal(t, "Pod", span.attr(AttributeResource))
+	assert.Equal(t, "ns", span.attr(AttributeNamespace))
+	assert.Equal(t, "b", span.attr(AttributeName))
+	assert.Equal(t, "6", span.attr(AttributeResourceVersion))
+	assert.Equal(t, "create", span.attr(AttributeEventType))
+	assert.Equal(t, 1, span.attr(AttributeSubscribers))
+
+	listed := tracer.find("kcache.list")
+	require.NotNil(t, listed)
+	assert.True(t, listed.isEnded())
+	assert.Equal(t, "Pod", listed.attr(AttributeResource))
+	assert.Equal(t, "ns", listed.attr(AttributeNamespace))
+	assert.Equal(t, "5", listed.attr(AttributeResourceVersion))
+	assert.Equal(t, 1, listed.attr(AttributeObjects))
+
+	synced := tracer.find("kcache.cache.sync")
+	require.NotNil(t, synced)
+	assert.Equal(t, 1, synced.attr(AttributeEvents))
+
+	require.NotNil(t, tracer.find("kcache.watch.connect"))
+
+	session := tracer.find("kcache.watch.session")
+	require.NotNil(t, session)
+	assert.Equal(t, "Pod", session.attr(AttributeResource))
+	assert.Equal(t, "ns", session.attr(AttributeNamespace))
+	assert.Equal(t, "5", session.attr(AttributeResourceVersion))
+
+	controller.Close()
+	testutil.AssertDone(t, "controller", controller)
+
+	assert.Eventually(t, func() bool {
+		session := tracer.find("kcache.watch.session")
+		return session != nil && session.isEnded() && session.attr(AttributeEvents) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+type testSpanKey struct{}
+
+type testScopedClient struct {
+	*mocks.Client
+	namespace string
+}
+
+func (c testScopedClient) Resource() string {
+	return "pods"
+}
+
+func (c testScopedClient) Namespace() string {
+	return c.namespace
+}
+
+type testTracer struct {
+	spans []*testSpan
+	mtx   sync.Mutex
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	parent, _ := ctx.Value(testSpanKey{}).(*testSpan)
+	span := &testSpan{name: name, parent: parent, attrs: make(map[string]interface{})}
+	span.SetAttributes(attrs...)
+
+	t.mtx.Lock()
+	t.spans = append(t.spans, span)
+	t.mtx.Unlock()
+
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func (t *testTracer) find(name string) *testSpan {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	for _, span := range t.spans {
+		if span.name == name {
+			return span
+		}
+	}
+	return nil
+}
+
+type testSpan struct {
+	name   string
+	parent *testSpan
+	attrs  map[string]interface{}
+	ended  bool
+	err    error
+	mtx    sync.Mutex
+}
+
+func (s *testSpan) SetAttributes(attrs ...Attribute) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *testSpan) End(err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.ended = true
+	s.err = err
+}
+
+func (s *testSpan) isEnded() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.ended
+}
+
+func (s *testSpan) attr(key string) interface{} {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.attrs[key]
+}
+
+func (s *testSpan) lineage() []string {
+	var names []string
+	for ; s != nil; s = s.parent {
+		names = append(names, s.name)
+	}
+	return names
+}
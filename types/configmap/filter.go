@@ -0,0 +1,135 @@
+package configmap
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	filter.RegisterFunc("configmap.pods", func(args []string) (filter.ComparableFilter, error) {
+		ids, err := parseIDs(args)
+		if err != nil {
+			return nil, err
+		}
+		return podsFilter(ids), nil
+	})
+	filter.RegisterKind("configmap.pods", func(spec json.RawMessage) (filter.ComparableFilter, error) {
+		var args []string
+		if err := json.Unmarshal(spec, &args); err != nil {
+			return nil, err
+		}
+		ids, err := parseIDs(args)
+		if err != nil {
+			return nil, err
+		}
+		return podsFilter(ids), nil
+	})
+}
+
+// PodsFilter() returns a filter which accepts pods that reference one of the
+// given config maps through a volume or their containers' environment.
+func PodsFilter(sources ...*corev1.ConfigMap) filter.ComparableFilter {
+	ids := make(map[nsname.NSName]bool, len(sources))
+	for _, cm := range sources {
+		ids[nsname.ForObject(cm)] = true
+	}
+	return podsFilter(ids)
+}
+
+type podsFilter map[nsname.NSName]bool
+
+func (f podsFilter) Accept(obj metav1.Object) bool {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return false
+	}
+	for _, name := range podConfigMaps(pod) {
+		if f[nsname.New(pod.Namespace, name)] {
+			return true
+		}
+	}
+	return false
+}
+
+func (f podsFilter) Equals(other filter.Filter) bool {
+	o, ok := other.(podsFilter)
+	if !ok || len(f) != len(o) {
+		return false
+	}
+	for id := range f {
+		if !o[id] {
+			return false
+		}
+	}
+	return true
+}
+
+func (f podsFilter) String() string {
+	return filter.Call("configmap.pods", f.ids()...)
+}
+
+func (f podsFilter) MarshalJSON() ([]byte, error) {
+	return filter.MarshalKind("configmap.pods", f.ids())
+}
+
+func (f podsFilter) ids() []string {
+	ids := make([]string, 0, len(f))
+	for id := range f {
+		ids = append(ids, id.String())
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func parseIDs(args []string) (map[nsname.NSName]bool, error) {
+	ids := make(map[nsname.NSName]bool, len(args))
+	for _, arg := range args {
+		id, err := nsname.Parse(arg)
+		if err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+	return ids, nil
+}
+
+// podConfigMaps() returns the names of the config maps referenced by pod.
+func podConfigMaps(pod *corev1.Pod) []string {
+	var names []string
+
+	for _, v := range pod.Spec.Volumes {
+		if v.ConfigMap != nil {
+			names = append(names, v.ConfigMap.Name)
+		}
+		if v.Projected != nil {
+			for _, src := range v.Projected.Sources {
+				if src.ConfigMap != nil {
+					names = append(names, src.ConfigMap.Name)
+				}
+			}
+		}
+	}
+
+	containers := append([]corev1.Container{}, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+
+	for _, c := range containers {
+		for _, src := range c.EnvFrom {
+			if src.ConfigMapRef != nil {
+				names = append(names, src.ConfigMapRef.Name)
+			}
+		}
+		for _, env := range c.Env {
+			if env.ValueFrom != nil && env.ValueFrom.ConfigMapKeyRef != nil {
+				names = append(names, env.ValueFrom.ConfigMapKeyRef.Name)
+			}
+		}
+	}
+
+	return names
+}
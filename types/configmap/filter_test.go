@@ -0,0 +1,79 @@
+package configmap_test
+
+import (
+	"testing"
+
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/types/configmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodsFilter(t *testing.T) {
+	gencm := func(ns, name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+	}
+	genpod := func(ns string, spec corev1.PodSpec) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "pod"}, Spec: spec}
+	}
+	ref := corev1.LocalObjectReference{Name: "cm"}
+
+	volume := genpod("a", corev1.PodSpec{Volumes: []corev1.Volume{{
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: ref}},
+	}}})
+	projected := genpod("a", corev1.PodSpec{Volumes: []corev1.Volume{{
+		VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+			Sources: []corev1.VolumeProjection{{ConfigMap: &corev1.ConfigMapProjection{LocalObjectReference: ref}}},
+		}},
+	}}})
+	envFrom := genpod("a", corev1.PodSpec{Containers: []corev1.Container{{
+		EnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: ref}}},
+	}}})
+	env := genpod("a", corev1.PodSpec{InitContainers: []corev1.Container{{
+		Env: []corev1.EnvVar{{ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: ref}}}},
+	}}})
+	other := genpod("b", volume.Spec)
+	none := genpod("a", corev1.PodSpec{})
+
+	f := configmap.PodsFilter(gencm("a", "cm"))
+	assert.True(t, f.Accept(volume))
+	assert.True(t, f.Accept(projected))
+	assert.True(t, f.Accept(envFrom))
+	assert.True(t, f.Accept(env))
+	assert.False(t, f.Accept(other))
+	assert.False(t, f.Accept(none))
+	assert.False(t, f.Accept(gencm("a", "cm")))
+
+	assert.False(t, configmap.PodsFilter().Accept(volume))
+	assert.True(t, configmap.PodsFilter(gencm("b", "cm"), gencm("a", "x")).Accept(other))
+
+	assert.True(t, f.Equals(configmap.PodsFilter(gencm("a", "cm"))))
+	assert.False(t, f.Equals(configmap.PodsFilter(gencm("b", "cm"))))
+	assert.True(t, configmap.PodsFilter(gencm("a", "1"), gencm("a", "2")).
+		Equals(configmap.PodsFilter(gencm("a", "2"), gencm("a", "1"))))
+}
+
+func TestPodsFilter_serialize(t *testing.T) {
+	f := configmap.PodsFilter(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "b", Name: "2"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "1"}},
+	)
+
+	expr := f.(interface{ String() string }).String()
+	assert.Equal(t, "configmap.pods(a/1, b/2)", expr)
+
+	parsed, err := filter.Parse(expr)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(parsed))
+
+	buf, err := filter.Marshal(f)
+	require.NoError(t, err)
+	decoded, err := filter.Unmarshal(buf)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(decoded))
+
+	_, err = filter.Parse(`configmap.pods(a)`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,46 @@
+package cronjob
+
+import (
+	"sort"
+
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// JobsFilter() returns a filter which accepts the jobs
+// controlled by the given cron jobs.
+func JobsFilter(sources ...*batchv1.CronJob) filter.ComparableFilter {
+
+	// make a copy and sort
+	srcs := make([]*batchv1.CronJob, len(sources))
+	copy(srcs, sources)
+
+	sort.Slice(srcs, func(i, j int) bool {
+		if srcs[i].Namespace != srcs[j].Namespace {
+			return srcs[i].Namespace < srcs[j].Namespace
+		}
+		return srcs[i].Name < srcs[j].Name
+	})
+
+	filters := make([]filter.Filter, 0, len(srcs))
+
+	for _, cj := range srcs {
+		nsfilter := filter.NSName(nsname.New(cj.GetNamespace(), ""))
+		cfilter := filter.ControlledBy("CronJob", cj.GetName())
+		filters = append(filters, filter.And(nsfilter, cfilter))
+	}
+
+	return filter.Or(filters...)
+}
+
+// OwnedJobsFilter() returns a filter which accepts jobs owned
+// by the given cron jobs.
+func OwnedJobsFilter(sources ...*batchv1.CronJob) filter.ComparableFilter {
+	objs := make([]metav1.Object, 0, len(sources))
+	for _, obj := range sources {
+		objs = append(objs, obj)
+	}
+	return filter.DependentsOf(objs...)
+}
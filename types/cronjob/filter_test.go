@@ -0,0 +1,59 @@
+package cronjob_test
+
+import (
+	"testing"
+
+	"github.com/boz/kcache/types/cronjob"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestJobsFilter(t *testing.T) {
+	gencj := func(ns, name string) *batchv1.CronJob {
+		return &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+	}
+	genjob := func(ns, kind, owner string, controller bool) *batchv1.Job {
+		return &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      owner + "-123",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: kind, Name: owner, Controller: &controller},
+			},
+		}}
+	}
+
+	f := cronjob.JobsFilter(gencj("a", "1"), gencj("b", "2"))
+	assert.True(t, f.Accept(genjob("a", "CronJob", "1", true)))
+	assert.True(t, f.Accept(genjob("b", "CronJob", "2", true)))
+	assert.False(t, f.Accept(genjob("a", "CronJob", "1", false)))
+	assert.False(t, f.Accept(genjob("b", "CronJob", "1", true)))
+	assert.False(t, f.Accept(genjob("a", "Deployment", "1", true)))
+
+	assert.False(t, cronjob.JobsFilter().Accept(genjob("a", "CronJob", "1", true)))
+
+	assert.True(t, f.Equals(cronjob.JobsFilter(gencj("b", "2"), gencj("a", "1"))))
+	assert.False(t, f.Equals(cronjob.JobsFilter(gencj("a", "1"))))
+}
+
+func TestOwnedJobsFilter(t *testing.T) {
+	gencj := func(name, uid string) *batchv1.CronJob {
+		return &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: name, UID: types.UID(uid)}}
+	}
+	genjob := func(owner, uid string) *batchv1.Job {
+		return &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "a",
+			Name:      owner + "-123",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "CronJob", Name: owner, UID: types.UID(uid)},
+			},
+		}}
+	}
+
+	f := cronjob.OwnedJobsFilter(gencj("1", "uid-1"))
+	assert.True(t, f.Accept(genjob("1", "uid-1")))
+
+	// a job left behind by a deleted cron job of the same name.
+	assert.False(t, f.Accept(genjob("1", "uid-0")))
+}
@@ -32,6 +32,14 @@ type Event interface {
 type CacheReader interface {
 	Get(ns string, name string) (*appsv1.DaemonSet, error)
 	List() ([]*appsv1.DaemonSet, error)
+	Snapshot() (Snapshot, error)
+}
+
+type Snapshot interface {
+	ResourceVersion() string
+	Get(ns string, name string) *appsv1.DaemonSet
+	List() []*appsv1.DaemonSet
+	ByIndex(index string, value string) ([]*appsv1.DaemonSet, error)
 }
 
 type CacheController interface {
@@ -61,16 +69,21 @@ type Controller interface {
 	Done() <-chan struct{}
 	Close()
 	Error() error
+	Untyped() kcache.Controller
 }
 
 type FilterSubscription interface {
 	Subscription
 	Refilter(filter.Filter) error
+	Filter() filter.Filter
+	CloseWithError(error)
 }
 
 type FilterController interface {
 	Controller
 	Refilter(filter.Filter) error
+	Filter() filter.Filter
+	CloseWithError(error)
 }
 
 type BaseHandler interface {
@@ -154,6 +167,44 @@ func (c *cache) List() ([]*appsv1.DaemonSet, error) {
 	return adapter.adaptList(objs)
 }
 
+func (c *cache) Snapshot() (Snapshot, error) {
+	parent, err := c.parent.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return snapshot{parent}, nil
+}
+
+type snapshot struct {
+	parent kcache.Snapshot
+}
+
+func (s snapshot) ResourceVersion() string {
+	return s.parent.ResourceVersion()
+}
+
+func (s snapshot) Get(ns string, name string) *appsv1.DaemonSet {
+	obj := s.parent.Get(ns, name)
+	if obj == nil {
+		return nil
+	}
+	adapted, _ := adapter.adaptObject(obj)
+	return adapted
+}
+
+func (s snapshot) List() []*appsv1.DaemonSet {
+	objs, _ := adapter.adaptList(s.parent.List())
+	return objs
+}
+
+func (s snapshot) ByIndex(index string, value string) ([]*appsv1.DaemonSet, error) {
+	objs, err := s.parent.ByIndex(index, value)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.adaptList(objs)
+}
+
 type event struct {
 	etype    kcache.EventType
 	resource *appsv1.DaemonSet
@@ -267,6 +318,10 @@ func (c *controller) Cache() CacheReader {
 	return c.cache
 }
 
+func (c *controller) Untyped() kcache.Controller {
+	return c.parent
+}
+
 func (c *controller) Subscribe() (Subscription, error) {
 	parent, err := c.parent.Subscribe()
 	if err != nil {
@@ -331,6 +386,14 @@ func (c *filterController) Refilter(f filter.Filter) error {
 	return c.filterParent.Refilter(f)
 }
 
+func (c *filterController) Filter() filter.Filter {
+	return c.filterParent.Filter()
+}
+
+func (c *filterController) CloseWithError(err error) {
+	c.filterParent.CloseWithError(err)
+}
+
 type filterSubscription struct {
 	subscription
 	filterParent kcache.FilterSubscription
@@ -347,6 +410,14 @@ func (s *filterSubscription) Refilter(f filter.Filter) error {
 	return s.filterParent.Refilter(f)
 }
 
+func (s *filterSubscription) Filter() filter.Filter {
+	return s.filterParent.Filter()
+}
+
+func (s *filterSubscription) CloseWithError(err error) {
+	s.filterParent.CloseWithError(err)
+}
+
 func NewMonitor(publisher Publisher, handler Handler) (kcache.Monitor, error) {
 	phandler := kcache.BuildHandler().
 		OnInitialize(func(objs []metav1.Object) {
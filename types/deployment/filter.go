@@ -1,13 +1,84 @@
 package deployment
 
 import (
+	"encoding/json"
 	"sort"
 
 	"github.com/boz/kcache/filter"
 	"github.com/boz/kcache/nsname"
 	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
+func init() {
+	filter.RegisterRawFunc("deployment.selects", func(raw string) (filter.ComparableFilter, error) {
+		target, err := labels.ConvertSelectorToLabelsMap(raw)
+		if err != nil {
+			return nil, err
+		}
+		return SelectorMatchFilter(target), nil
+	})
+	filter.RegisterKind("deployment.selects", func(spec json.RawMessage) (filter.ComparableFilter, error) {
+		var target map[string]string
+		if err := json.Unmarshal(spec, &target); err != nil {
+			return nil, err
+		}
+		return SelectorMatchFilter(target), nil
+	})
+}
+
+// SelectorMatchFilter() removes all objects that are not deployments whose
+// selector matches the given target.
+func SelectorMatchFilter(target map[string]string) filter.ComparableFilter {
+	return &deploymentForFilter{target}
+}
+
+type deploymentForFilter struct {
+	target map[string]string
+}
+
+// Accept() returns true if the object is a Deployment whose
+// selector matches the target fields of the filter.
+func (f *deploymentForFilter) Accept(obj metav1.Object) bool {
+	dep, ok := obj.(*appsv1.Deployment)
+
+	if !ok || len(f.target) == 0 {
+		return false
+	}
+
+	var sel labels.Selector
+	if dep.Spec.Selector != nil {
+		var err error
+		if sel, err = metav1.LabelSelectorAsSelector(dep.Spec.Selector); err != nil {
+			return false
+		}
+	} else {
+		sel = labels.SelectorFromSet(dep.Spec.Template.Labels)
+	}
+
+	if sel.Empty() {
+		return false
+	}
+
+	return sel.Matches(labels.Set(f.target))
+}
+
+func (f *deploymentForFilter) Equals(other filter.Filter) bool {
+	if other, ok := other.(*deploymentForFilter); ok {
+		return labels.Equals(f.target, other.target)
+	}
+	return false
+}
+
+func (f *deploymentForFilter) String() string {
+	return "deployment.selects(" + labels.Set(f.target).String() + ")"
+}
+
+func (f *deploymentForFilter) MarshalJSON() ([]byte, error) {
+	return filter.MarshalKind("deployment.selects", f.target)
+}
+
 func PodsFilter(sources ...*appsv1.Deployment) filter.ComparableFilter {
 
 	// make a copy and sort
@@ -40,3 +111,13 @@ func PodsFilter(sources ...*appsv1.Deployment) filter.ComparableFilter {
 	return filter.Or(filters...)
 
 }
+
+// ReplicaSetsFilter() returns a filter which accepts replica sets
+// owned by the given deployments.
+func ReplicaSetsFilter(sources ...*appsv1.Deployment) filter.ComparableFilter {
+	objs := make([]metav1.Object, 0, len(sources))
+	for _, obj := range sources {
+		objs = append(objs, obj)
+	}
+	return filter.DependentsOf(objs...)
+}
@@ -3,8 +3,10 @@ package deployment_test
 import (
 	"testing"
 
+	"github.com/boz/kcache/filter"
 	"github.com/boz/kcache/types/deployment"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -74,3 +76,60 @@ func testPodsFilter(t *testing.T, gen func(string, string, map[string]string) *a
 	assert.True(t, deployment.PodsFilter(s4, s3).Equals(deployment.PodsFilter(s3, s4)), ctx)
 
 }
+
+func TestReplicaSetsFilter(t *testing.T) {
+	dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{UID: "dep"}}
+
+	owned := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", UID: "dep"}},
+	}}
+	other := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", UID: "other"}},
+	}}
+
+	assert.True(t, deployment.ReplicaSetsFilter(dep).Accept(owned))
+	assert.False(t, deployment.ReplicaSetsFilter(dep).Accept(other))
+	assert.True(t, deployment.ReplicaSetsFilter(dep).Equals(deployment.ReplicaSetsFilter(dep)))
+}
+
+func TestSelectorMatchFilter(t *testing.T) {
+	gendep := func(sel *metav1.LabelSelector, template map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{Spec: appsv1.DeploymentSpec{
+			Selector: sel,
+			Template: v1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: template}},
+		}}
+	}
+
+	f := deployment.SelectorMatchFilter(map[string]string{"a": "1", "b": "2"})
+
+	assert.True(t, f.Accept(gendep(&metav1.LabelSelector{MatchLabels: map[string]string{"a": "1"}}, nil)))
+	assert.False(t, f.Accept(gendep(&metav1.LabelSelector{MatchLabels: map[string]string{"a": "2"}}, nil)))
+	assert.True(t, f.Accept(gendep(&metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "b", Operator: metav1.LabelSelectorOpIn, Values: []string{"2", "3"}},
+		},
+	}, nil)))
+	assert.True(t, f.Accept(gendep(nil, map[string]string{"b": "2"})))
+	assert.False(t, f.Accept(gendep(&metav1.LabelSelector{}, nil)))
+	assert.False(t, f.Accept(gendep(nil, nil)))
+	assert.False(t, f.Accept(&v1.Pod{}))
+
+	assert.False(t, deployment.SelectorMatchFilter(nil).
+		Accept(gendep(&metav1.LabelSelector{MatchLabels: map[string]string{"a": "1"}}, nil)))
+
+	assert.True(t, f.Equals(deployment.SelectorMatchFilter(map[string]string{"b": "2", "a": "1"})))
+	assert.False(t, f.Equals(deployment.SelectorMatchFilter(map[string]string{"a": "1"})))
+
+	expr := f.(interface{ String() string }).String()
+	assert.Equal(t, "deployment.selects(a=1,b=2)", expr)
+
+	parsed, err := filter.Parse(expr)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(parsed))
+
+	buf, err := filter.Marshal(f)
+	require.NoError(t, err)
+	decoded, err := filter.Unmarshal(buf)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(decoded))
+}
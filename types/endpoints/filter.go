@@ -0,0 +1,43 @@
+package endpoints
+
+import (
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ServicesFilter() returns a filter which accepts the services
+// that own the given endpoints.
+func ServicesFilter(sources ...*corev1.Endpoints) filter.ComparableFilter {
+	ids := make([]nsname.NSName, 0, len(sources))
+	for _, ep := range sources {
+		ids = append(ids, nsname.ForObject(ep))
+	}
+	return filter.NSName(ids...)
+}
+
+// PodsFilter() returns a filter which accepts the pods
+// addressed by the given endpoints, ready or not.
+func PodsFilter(sources ...*corev1.Endpoints) filter.ComparableFilter {
+	var ids []nsname.NSName
+	for _, ep := range sources {
+		for _, subset := range ep.Subsets {
+			for _, addr := range append(subset.Addresses, subset.NotReadyAddresses...) {
+				if id, ok := podRef(ep.Namespace, addr.TargetRef); ok {
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	return filter.NSName(ids...)
+}
+
+func podRef(ns string, ref *corev1.ObjectReference) (nsname.NSName, bool) {
+	if ref == nil || ref.Kind != "Pod" || ref.Name == "" {
+		return nsname.NSName{}, false
+	}
+	if ref.Namespace != "" {
+		ns = ref.Namespace
+	}
+	return nsname.New(ns, ref.Name), true
+}
@@ -0,0 +1,57 @@
+package endpoints_test
+
+import (
+	"testing"
+
+	"github.com/boz/kcache/types/endpoints"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func genpod(ns, name string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+}
+
+func TestServicesFilter(t *testing.T) {
+	ep := &corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "web"}}
+	gensvc := func(ns, name string) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+	}
+
+	f := endpoints.ServicesFilter(ep)
+	assert.True(t, f.Accept(gensvc("a", "web")))
+	assert.False(t, f.Accept(gensvc("b", "web")))
+	assert.False(t, f.Accept(gensvc("a", "api")))
+	assert.False(t, endpoints.ServicesFilter().Accept(gensvc("a", "web")))
+}
+
+func TestPodsFilter(t *testing.T) {
+	podref := func(ns, name string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{Kind: "Pod", Namespace: ns, Name: name}
+	}
+
+	ep := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "web"},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{
+				{TargetRef: podref("a", "1")},
+				{TargetRef: podref("", "2")},
+				{TargetRef: &corev1.ObjectReference{Kind: "Node", Name: "3"}},
+				{},
+			},
+			NotReadyAddresses: []corev1.EndpointAddress{
+				{TargetRef: podref("a", "4")},
+			},
+		}},
+	}
+
+	f := endpoints.PodsFilter(ep)
+	assert.True(t, f.Accept(genpod("a", "1")))
+	assert.True(t, f.Accept(genpod("a", "2")))
+	assert.False(t, f.Accept(genpod("a", "3")))
+	assert.True(t, f.Accept(genpod("a", "4")))
+	assert.False(t, f.Accept(genpod("b", "1")))
+
+	assert.False(t, endpoints.PodsFilter().Accept(genpod("a", "1")))
+}
@@ -0,0 +1,34 @@
+package endpoints
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ metav1.Object
+var _ corev1.Pod
+var _ corev1.Secret
+var _ corev1.Service
+var _ corev1.Event
+var _ corev1.Node
+var _ corev1.ReplicationController
+var _ appsv1.Deployment
+var _ networkingv1.Ingress
+var _ appsv1.ReplicaSet
+var _ appsv1.DaemonSet
+var _ batchv1.Job
+var _ appsv1.StatefulSet
+var _ corev1.ConfigMap
+var _ corev1.Namespace
+var _ corev1.PersistentVolume
+var _ corev1.PersistentVolumeClaim
+var _ corev1.Endpoints
+var _ discoveryv1.EndpointSlice
+var _ batchv1.CronJob
+var _ autoscalingv1.HorizontalPodAutoscaler
+var _ corev1.ServiceAccount
@@ -0,0 +1,13 @@
+package endpointslice
+
+import (
+	"github.com/boz/kcache/client"
+	"k8s.io/client-go/kubernetes"
+)
+
+const resourceName = "endpointslices"
+
+func NewClient(cs kubernetes.Interface, ns string) client.Client {
+	scope := cs.DiscoveryV1()
+	return client.ForResource(scope.RESTClient(), resourceName, ns)
+}
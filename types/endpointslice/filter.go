@@ -0,0 +1,44 @@
+package endpointslice
+
+import (
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+// ServicesFilter() returns a filter which accepts the services
+// that own the given endpoint slices.
+func ServicesFilter(sources ...*discoveryv1.EndpointSlice) filter.ComparableFilter {
+	ids := make([]nsname.NSName, 0, len(sources))
+	for _, slice := range sources {
+		if name := slice.Labels[discoveryv1.LabelServiceName]; name != "" {
+			ids = append(ids, nsname.New(slice.Namespace, name))
+		}
+	}
+	return filter.NSName(ids...)
+}
+
+// PodsFilter() returns a filter which accepts the pods
+// targeted by the given endpoint slices.
+func PodsFilter(sources ...*discoveryv1.EndpointSlice) filter.ComparableFilter {
+	var ids []nsname.NSName
+	for _, slice := range sources {
+		for _, ep := range slice.Endpoints {
+			if id, ok := podRef(slice.Namespace, ep.TargetRef); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return filter.NSName(ids...)
+}
+
+func podRef(ns string, ref *corev1.ObjectReference) (nsname.NSName, bool) {
+	if ref == nil || ref.Kind != "Pod" || ref.Name == "" {
+		return nsname.NSName{}, false
+	}
+	if ref.Namespace != "" {
+		ns = ref.Namespace
+	}
+	return nsname.New(ns, ref.Name), true
+}
@@ -0,0 +1,54 @@
+package endpointslice_test
+
+import (
+	"testing"
+
+	"github.com/boz/kcache/types/endpointslice"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func genslice(ns, service string, refs ...*corev1.ObjectReference) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{
+		Namespace: ns,
+		Name:      service + "-abcde",
+		Labels:    map[string]string{discoveryv1.LabelServiceName: service},
+	}}
+	for _, ref := range refs {
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{TargetRef: ref})
+	}
+	return slice
+}
+
+func TestServicesFilter(t *testing.T) {
+	gensvc := func(ns, name string) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+	}
+
+	f := endpointslice.ServicesFilter(genslice("a", "web"), genslice("a", ""))
+	assert.True(t, f.Accept(gensvc("a", "web")))
+	assert.False(t, f.Accept(gensvc("a", "web-abcde")))
+	assert.False(t, f.Accept(gensvc("b", "web")))
+	assert.False(t, f.Accept(gensvc("a", "")))
+}
+
+func TestPodsFilter(t *testing.T) {
+	genpod := func(ns, name string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+	}
+
+	f := endpointslice.PodsFilter(genslice("a", "web",
+		&corev1.ObjectReference{Kind: "Pod", Namespace: "a", Name: "1"},
+		&corev1.ObjectReference{Kind: "Pod", Name: "2"},
+		&corev1.ObjectReference{Kind: "Node", Name: "3"},
+		nil,
+	))
+	assert.True(t, f.Accept(genpod("a", "1")))
+	assert.True(t, f.Accept(genpod("a", "2")))
+	assert.False(t, f.Accept(genpod("a", "3")))
+	assert.False(t, f.Accept(genpod("b", "1")))
+
+	assert.False(t, endpointslice.PodsFilter().Accept(genpod("a", "1")))
+}
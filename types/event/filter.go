@@ -1,10 +1,15 @@
 package event
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
 	"github.com/boz/kcache/filter"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 type Object interface {
@@ -13,6 +18,32 @@ type Object interface {
 	GetName() string
 }
 
+func init() {
+	filter.RegisterFunc("event.involved", func(args []string) (filter.ComparableFilter, error) {
+		if len(args) != 3 {
+			return nil, fmt.Errorf("expected 3 arguments (kind, namespace, name), got %v", len(args))
+		}
+		return InvolvedFilter(args[0], args[1], args[2]), nil
+	})
+	filter.RegisterKind("event.involved", func(spec json.RawMessage) (filter.ComparableFilter, error) {
+		var s involvedSpec
+		if err := json.Unmarshal(spec, &s); err != nil {
+			return nil, err
+		}
+		return InvolvedFilter(s.Kind, s.Namespace, s.Name), nil
+	})
+	filter.RegisterFunc("event.involvedUID", func(args []string) (filter.ComparableFilter, error) {
+		return InvolvedUIDFilter(toUIDs(args)...), nil
+	})
+	filter.RegisterKind("event.involvedUID", func(spec json.RawMessage) (filter.ComparableFilter, error) {
+		var args []string
+		if err := json.Unmarshal(spec, &args); err != nil {
+			return nil, err
+		}
+		return InvolvedUIDFilter(toUIDs(args)...), nil
+	})
+}
+
 func InvolvedObjectFilter(obj Object) filter.ComparableFilter {
 	kind := obj.GetObjectKind().GroupVersionKind().Kind
 	return InvolvedFilter(kind, obj.GetNamespace(), obj.GetName())
@@ -45,3 +76,88 @@ func (f *involvedFilter) Equals(other filter.Filter) bool {
 	}
 	return false
 }
+
+func (f *involvedFilter) String() string {
+	return filter.Call("event.involved", f.kind, f.ns, f.name)
+}
+
+type involvedSpec struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+func (f *involvedFilter) MarshalJSON() ([]byte, error) {
+	return filter.MarshalKind("event.involved", involvedSpec{f.kind, f.ns, f.name})
+}
+
+// InvolvedObjectsFilter() returns a filter which accepts events
+// whose involved object is one of the given objects.
+// Objects are matched by UID.
+func InvolvedObjectsFilter(objs ...metav1.Object) filter.ComparableFilter {
+	uids := make([]types.UID, 0, len(objs))
+	for _, obj := range objs {
+		uids = append(uids, obj.GetUID())
+	}
+	return InvolvedUIDFilter(uids...)
+}
+
+// InvolvedUIDFilter() returns a filter which accepts events
+// whose involved object has one of the given UIDs.
+func InvolvedUIDFilter(uids ...types.UID) filter.ComparableFilter {
+	set := make(map[types.UID]bool, len(uids))
+	for _, uid := range uids {
+		if uid != "" {
+			set[uid] = true
+		}
+	}
+	return involvedUIDFilter(set)
+}
+
+type involvedUIDFilter map[types.UID]bool
+
+func (f involvedUIDFilter) Accept(obj metav1.Object) bool {
+	event, ok := obj.(*corev1.Event)
+	if !ok {
+		return false
+	}
+	return f[event.InvolvedObject.UID]
+}
+
+func (f involvedUIDFilter) Equals(other filter.Filter) bool {
+	o, ok := other.(involvedUIDFilter)
+	if !ok || len(f) != len(o) {
+		return false
+	}
+	for uid := range f {
+		if !o[uid] {
+			return false
+		}
+	}
+	return true
+}
+
+func (f involvedUIDFilter) String() string {
+	return filter.Call("event.involvedUID", f.uids()...)
+}
+
+func (f involvedUIDFilter) MarshalJSON() ([]byte, error) {
+	return filter.MarshalKind("event.involvedUID", f.uids())
+}
+
+func (f involvedUIDFilter) uids() []string {
+	uids := make([]string, 0, len(f))
+	for uid := range f {
+		uids = append(uids, string(uid))
+	}
+	sort.Strings(uids)
+	return uids
+}
+
+func toUIDs(args []string) []types.UID {
+	uids := make([]types.UID, 0, len(args))
+	for _, arg := range args {
+		uids = append(uids, types.UID(arg))
+	}
+	return uids
+}
@@ -1,12 +1,16 @@
 package event_test
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/boz/kcache/filter"
 	"github.com/boz/kcache/types/event"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"k8s.io/api/core/v1"
 )
@@ -63,3 +67,60 @@ func TestInvolvedForFilter(t *testing.T) {
 		assert.False(t, f.Equals(event.InvolvedFilter("service", "a", "b")))
 	}
 }
+
+func TestInvolvedFilter_parse(t *testing.T) {
+	f := event.InvolvedFilter("Node", "", "node-1")
+	assert.Equal(t, `event.involved(Node, "", node-1)`, f.(fmt.Stringer).String())
+
+	parsed, err := filter.Parse(`event.involved(Node, "", node-1)`)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(parsed))
+
+	_, err = filter.Parse(`event.involved(Node)`)
+	assert.Error(t, err)
+}
+
+func TestInvolvedFilter_json(t *testing.T) {
+	f := event.InvolvedFilter("Pod", "a", "b")
+
+	buf, err := filter.Marshal(f)
+	require.NoError(t, err)
+
+	decoded, err := filter.Unmarshal(buf)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(decoded))
+}
+
+func TestInvolvedObjectsFilter(t *testing.T) {
+	genpod := func(uid string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{UID: types.UID(uid)}}
+	}
+	genevt := func(uid string) *v1.Event {
+		return &v1.Event{InvolvedObject: v1.ObjectReference{UID: types.UID(uid)}}
+	}
+
+	f := event.InvolvedObjectsFilter(genpod("a"), genpod("b"))
+	assert.True(t, f.Accept(genevt("a")))
+	assert.True(t, f.Accept(genevt("b")))
+	assert.False(t, f.Accept(genevt("c")))
+	assert.False(t, f.Accept(genpod("a")))
+
+	assert.False(t, event.InvolvedObjectsFilter().Accept(genevt("a")))
+	assert.False(t, event.InvolvedObjectsFilter(genpod("")).Accept(genevt("")))
+
+	assert.True(t, f.Equals(event.InvolvedUIDFilter("b", "a")))
+	assert.False(t, f.Equals(event.InvolvedUIDFilter("a")))
+
+	expr := f.(fmt.Stringer).String()
+	assert.Equal(t, "event.involvedUID(a, b)", expr)
+
+	parsed, err := filter.Parse(expr)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(parsed))
+
+	buf, err := filter.Marshal(f)
+	require.NoError(t, err)
+	decoded, err := filter.Unmarshal(buf)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(decoded))
+}
@@ -0,0 +1,13 @@
+package horizontalpodautoscaler
+
+import (
+	"github.com/boz/kcache/client"
+	"k8s.io/client-go/kubernetes"
+)
+
+const resourceName = "horizontalpodautoscalers"
+
+func NewClient(cs kubernetes.Interface, ns string) client.Client {
+	scope := cs.AutoscalingV1()
+	return client.ForResource(scope.RESTClient(), resourceName, ns)
+}
@@ -0,0 +1,19 @@
+package horizontalpodautoscaler
+
+import (
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+)
+
+// TargetsFilter() returns a filter which accepts the objects of the
+// given kind (for example, "Deployment") scaled by the given autoscalers.
+func TargetsFilter(kind string, sources ...*autoscalingv1.HorizontalPodAutoscaler) filter.ComparableFilter {
+	ids := make([]nsname.NSName, 0, len(sources))
+	for _, hpa := range sources {
+		if ref := hpa.Spec.ScaleTargetRef; ref.Kind == kind && ref.Name != "" {
+			ids = append(ids, nsname.New(hpa.Namespace, ref.Name))
+		}
+	}
+	return filter.NSName(ids...)
+}
@@ -0,0 +1,39 @@
+package horizontalpodautoscaler_test
+
+import (
+	"testing"
+
+	"github.com/boz/kcache/types/horizontalpodautoscaler"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTargetsFilter(t *testing.T) {
+	genhpa := func(ns, kind, name string) *autoscalingv1.HorizontalPodAutoscaler {
+		return &autoscalingv1.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{Kind: kind, Name: name},
+			},
+		}
+	}
+	gendeployment := func(ns, name string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+	}
+
+	hpas := []*autoscalingv1.HorizontalPodAutoscaler{
+		genhpa("a", "Deployment", "web"),
+		genhpa("a", "StatefulSet", "db"),
+	}
+
+	f := horizontalpodautoscaler.TargetsFilter("Deployment", hpas...)
+	assert.True(t, f.Accept(gendeployment("a", "web")))
+	assert.False(t, f.Accept(gendeployment("b", "web")))
+	assert.False(t, f.Accept(gendeployment("a", "db")))
+
+	f = horizontalpodautoscaler.TargetsFilter("StatefulSet", hpas...)
+	assert.True(t, f.Accept(&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "db"}}))
+	assert.False(t, f.Accept(gendeployment("a", "web")))
+}
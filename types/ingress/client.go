@@ -8,6 +8,6 @@ import (
 const resourceName = "ingresses"
 
 func NewClient(cs kubernetes.Interface, ns string) client.Client {
-	scope := cs.NetworkingV1beta1()
+	scope := cs.NetworkingV1()
 	return client.ForResource(scope.RESTClient(), resourceName, ns)
 }
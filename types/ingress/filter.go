@@ -1,37 +1,223 @@
 package ingress
 
 import (
+	"encoding/json"
+	"sort"
+
 	"github.com/boz/kcache/filter"
 	"github.com/boz/kcache/nsname"
-	appsv1 "k8s.io/api/networking/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func ServicesFilter(ingresses ...*appsv1.Ingress) filter.ComparableFilter {
+// ClassAnnotation is the deprecated annotation used to
+// set an ingress's class before spec.ingressClassName.
+const ClassAnnotation = "kubernetes.io/ingress.class"
+
+func init() {
+	filter.RegisterFunc("ingress.class", func(args []string) (filter.ComparableFilter, error) {
+		return ClassFilter(args...), nil
+	})
+	filter.RegisterKind("ingress.class", func(spec json.RawMessage) (filter.ComparableFilter, error) {
+		var names []string
+		if err := json.Unmarshal(spec, &names); err != nil {
+			return nil, err
+		}
+		return ClassFilter(names...), nil
+	})
+	filter.RegisterFunc("ingress.backends", func(args []string) (filter.ComparableFilter, error) {
+		ids, err := parseIDs(args)
+		if err != nil {
+			return nil, err
+		}
+		return backendsFilter(ids), nil
+	})
+	filter.RegisterKind("ingress.backends", func(spec json.RawMessage) (filter.ComparableFilter, error) {
+		var args []string
+		if err := json.Unmarshal(spec, &args); err != nil {
+			return nil, err
+		}
+		ids, err := parseIDs(args)
+		if err != nil {
+			return nil, err
+		}
+		return backendsFilter(ids), nil
+	})
+}
+
+// ServicesFilter() returns a filter which accepts the services
+// used as backends by the given ingresses.
+func ServicesFilter(ingresses ...*networkingv1.Ingress) filter.ComparableFilter {
 	var ids []nsname.NSName
 
 	for _, ing := range ingresses {
-		ids = append(ids, buildServicesFilter(ing)...)
+		for _, be := range backends(ing) {
+			if svc := be.Service; svc != nil && svc.Name != "" {
+				ids = append(ids, nsname.New(ing.GetNamespace(), svc.Name))
+			}
+		}
 	}
 
 	return filter.NSName(ids...)
 }
 
-func buildServicesFilter(ing *appsv1.Ingress) []nsname.NSName {
+// ResourcesFilter() returns a filter which accepts the objects of the
+// given kind used as resource backends by the given ingresses.
+func ResourcesFilter(kind string, ingresses ...*networkingv1.Ingress) filter.ComparableFilter {
 	var ids []nsname.NSName
 
-	if be := ing.Spec.Backend; be != nil && be.ServiceName != "" {
-		ids = append(ids, nsname.New(ing.GetNamespace(), be.ServiceName))
+	for _, ing := range ingresses {
+		for _, be := range backends(ing) {
+			if res := be.Resource; res != nil && res.Kind == kind && res.Name != "" {
+				ids = append(ids, nsname.New(ing.GetNamespace(), res.Name))
+			}
+		}
+	}
+
+	return filter.NSName(ids...)
+}
+
+// BackendsFilter() returns a filter which accepts ingresses that
+// use one of the given services as a backend.
+func BackendsFilter(services ...nsname.NSName) filter.ComparableFilter {
+	ids := make(map[nsname.NSName]bool, len(services))
+	for _, id := range services {
+		ids[id] = true
+	}
+	return backendsFilter(ids)
+}
+
+type backendsFilter map[nsname.NSName]bool
+
+func (f backendsFilter) Accept(obj metav1.Object) bool {
+	ing, ok := obj.(*networkingv1.Ingress)
+	if !ok {
+		return false
+	}
+	for _, be := range backends(ing) {
+		if svc := be.Service; svc != nil && f[nsname.New(ing.GetNamespace(), svc.Name)] {
+			return true
+		}
+	}
+	return false
+}
+
+func (f backendsFilter) Equals(other filter.Filter) bool {
+	o, ok := other.(backendsFilter)
+	if !ok || len(f) != len(o) {
+		return false
+	}
+	for id := range f {
+		if !o[id] {
+			return false
+		}
+	}
+	return true
+}
+
+func (f backendsFilter) String() string {
+	return filter.Call("ingress.backends", f.ids()...)
+}
+
+func (f backendsFilter) MarshalJSON() ([]byte, error) {
+	return filter.MarshalKind("ingress.backends", f.ids())
+}
+
+func (f backendsFilter) ids() []string {
+	ids := make([]string, 0, len(f))
+	for id := range f {
+		ids = append(ids, id.String())
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func parseIDs(args []string) (map[nsname.NSName]bool, error) {
+	ids := make(map[nsname.NSName]bool, len(args))
+	for _, arg := range args {
+		id, err := nsname.Parse(arg)
+		if err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+	return ids, nil
+}
+
+func backends(ing *networkingv1.Ingress) []networkingv1.IngressBackend {
+	var result []networkingv1.IngressBackend
+
+	if be := ing.Spec.DefaultBackend; be != nil {
+		result = append(result, *be)
 	}
 
 	for _, rule := range ing.Spec.Rules {
 		if http := rule.HTTP; http != nil {
 			for _, path := range http.Paths {
-				if service := path.Backend.ServiceName; service != "" {
-					ids = append(ids, nsname.New(ing.GetNamespace(), service))
-				}
+				result = append(result, path.Backend)
 			}
 		}
 	}
 
-	return ids
+	return result
+}
+
+// Class() returns the class of the ingress from spec.ingressClassName,
+// falling back to the deprecated annotation.
+func Class(ing *networkingv1.Ingress) string {
+	if name := ing.Spec.IngressClassName; name != nil && *name != "" {
+		return *name
+	}
+	return ing.GetAnnotations()[ClassAnnotation]
+}
+
+// ClassFilter() returns a filter which accepts ingresses
+// that have one of the given classes.
+func ClassFilter(names ...string) filter.ComparableFilter {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+	return classFilter(set)
+}
+
+type classFilter map[string]struct{}
+
+func (f classFilter) Accept(obj metav1.Object) bool {
+	ing, ok := obj.(*networkingv1.Ingress)
+	if !ok {
+		return false
+	}
+	_, ok = f[Class(ing)]
+	return ok
+}
+
+func (f classFilter) Equals(other filter.Filter) bool {
+	o, ok := other.(classFilter)
+	if !ok || len(f) != len(o) {
+		return false
+	}
+	for name := range f {
+		if _, ok := o[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (f classFilter) String() string {
+	return filter.Call("ingress.class", f.names()...)
+}
+
+func (f classFilter) MarshalJSON() ([]byte, error) {
+	return filter.MarshalKind("ingress.class", f.names())
+}
+
+func (f classFilter) names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
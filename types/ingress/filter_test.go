@@ -3,11 +3,14 @@ package ingress_test
 import (
 	"testing"
 
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
 	"github.com/boz/kcache/types/ingress"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"k8s.io/api/core/v1"
-	"k8s.io/api/networking/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -19,26 +22,31 @@ func TestServicesFilter(t *testing.T) {
 		}
 	}
 
-	ing1 := &v1beta1.Ingress{
+	ing1 := &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "1"},
-		Spec: v1beta1.IngressSpec{
-			Backend: &v1beta1.IngressBackend{
-				ServiceName: "foo",
+		Spec: networkingv1.IngressSpec{
+			DefaultBackend: &networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: "foo"},
 			},
 		},
 	}
 
-	ing2 := &v1beta1.Ingress{
+	ing2 := &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{Namespace: "b", Name: "2"},
-		Spec: v1beta1.IngressSpec{
-			Rules: []v1beta1.IngressRule{
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
 				{
-					IngressRuleValue: v1beta1.IngressRuleValue{
-						HTTP: &v1beta1.HTTPIngressRuleValue{
-							Paths: []v1beta1.HTTPIngressPath{
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
 								{
-									Backend: v1beta1.IngressBackend{
-										ServiceName: "bar",
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{Name: "bar"},
+									},
+								},
+								{
+									Backend: networkingv1.IngressBackend{
+										Resource: &v1.TypedLocalObjectReference{Kind: "Bucket", Name: "baz"},
 									},
 								},
 							},
@@ -56,6 +64,7 @@ func TestServicesFilter(t *testing.T) {
 	assert.True(t, ingress.ServicesFilter(ing2).Accept(gensvc("b", "bar")))
 	assert.False(t, ingress.ServicesFilter(ing2).Accept(gensvc("b", "foo")))
 	assert.False(t, ingress.ServicesFilter(ing2).Accept(gensvc("a", "bar")))
+	assert.False(t, ingress.ServicesFilter(ing2).Accept(gensvc("b", "baz")))
 
 	assert.True(t, ingress.ServicesFilter(ing1, ing2).Accept(gensvc("a", "foo")))
 	assert.True(t, ingress.ServicesFilter(ing1, ing2).Accept(gensvc("b", "bar")))
@@ -64,4 +73,83 @@ func TestServicesFilter(t *testing.T) {
 	assert.False(t, ingress.ServicesFilter(ing1).Equals(ingress.ServicesFilter(ing2)))
 	assert.True(t, ingress.ServicesFilter(ing1, ing2).Equals(ingress.ServicesFilter(ing2, ing1)))
 
+	bucket := func(ns, name string) metav1.Object {
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+	}
+
+	assert.True(t, ingress.ResourcesFilter("Bucket", ing2).Accept(bucket("b", "baz")))
+	assert.False(t, ingress.ResourcesFilter("Bucket", ing2).Accept(bucket("b", "bar")))
+	assert.False(t, ingress.ResourcesFilter("Bucket", ing1).Accept(bucket("b", "baz")))
+	assert.False(t, ingress.ResourcesFilter("Other", ing2).Accept(bucket("b", "baz")))
+}
+
+func TestClassFilter(t *testing.T) {
+	className := "nginx"
+
+	byName := &networkingv1.Ingress{Spec: networkingv1.IngressSpec{IngressClassName: &className}}
+	byAnnotation := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{ingress.ClassAnnotation: "traefik"},
+	}}
+	none := &networkingv1.Ingress{}
+
+	assert.Equal(t, "nginx", ingress.Class(byName))
+	assert.Equal(t, "traefik", ingress.Class(byAnnotation))
+	assert.Equal(t, "", ingress.Class(none))
+
+	f := ingress.ClassFilter("nginx", "traefik")
+	assert.True(t, f.Accept(byName))
+	assert.True(t, f.Accept(byAnnotation))
+	assert.False(t, f.Accept(none))
+	assert.False(t, f.Accept(&v1.Service{}))
+
+	assert.True(t, ingress.ClassFilter("").Accept(none))
+
+	assert.True(t, f.Equals(ingress.ClassFilter("traefik", "nginx")))
+	assert.False(t, f.Equals(ingress.ClassFilter("nginx")))
+
+	expr := f.(interface{ String() string }).String()
+	assert.Equal(t, "ingress.class(nginx, traefik)", expr)
+
+	parsed, err := filter.Parse(expr)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(parsed))
+
+	buf, err := filter.Marshal(f)
+	require.NoError(t, err)
+	decoded, err := filter.Unmarshal(buf)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(decoded))
+}
+
+func TestBackendsFilter(t *testing.T) {
+	ing := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "1"},
+		Spec: networkingv1.IngressSpec{
+			DefaultBackend: &networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: "foo"},
+			},
+		},
+	}
+
+	f := ingress.BackendsFilter(nsname.New("a", "foo"), nsname.New("b", "bar"))
+	assert.True(t, f.Accept(ing))
+	assert.False(t, ingress.BackendsFilter(nsname.New("b", "foo")).Accept(ing))
+	assert.False(t, ingress.BackendsFilter().Accept(ing))
+	assert.False(t, f.Accept(&v1.Service{}))
+
+	assert.True(t, f.Equals(ingress.BackendsFilter(nsname.New("b", "bar"), nsname.New("a", "foo"))))
+	assert.False(t, f.Equals(ingress.BackendsFilter(nsname.New("a", "foo"))))
+
+	expr := f.(interface{ String() string }).String()
+	assert.Equal(t, "ingress.backends(a/foo, b/bar)", expr)
+
+	parsed, err := filter.Parse(expr)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(parsed))
+
+	buf, err := filter.Marshal(f)
+	require.NoError(t, err)
+	decoded, err := filter.Unmarshal(buf)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(decoded))
 }
@@ -13,7 +13,7 @@ import (
 	"github.com/boz/kcache/client"
 	"github.com/boz/kcache/filter"
 	appsv1 "k8s.io/api/apps/v1"
-	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -27,12 +27,20 @@ var _ = appsv1.Deployment{}
 
 type Event interface {
 	Type() kcache.EventType
-	Resource() *networkingv1beta1.Ingress
+	Resource() *networkingv1.Ingress
 }
 
 type CacheReader interface {
-	Get(ns string, name string) (*networkingv1beta1.Ingress, error)
-	List() ([]*networkingv1beta1.Ingress, error)
+	Get(ns string, name string) (*networkingv1.Ingress, error)
+	List() ([]*networkingv1.Ingress, error)
+	Snapshot() (Snapshot, error)
+}
+
+type Snapshot interface {
+	ResourceVersion() string
+	Get(ns string, name string) *networkingv1.Ingress
+	List() []*networkingv1.Ingress
+	ByIndex(index string, value string) ([]*networkingv1.Ingress, error)
 }
 
 type CacheController interface {
@@ -62,61 +70,66 @@ type Controller interface {
 	Done() <-chan struct{}
 	Close()
 	Error() error
+	Untyped() kcache.Controller
 }
 
 type FilterSubscription interface {
 	Subscription
 	Refilter(filter.Filter) error
+	Filter() filter.Filter
+	CloseWithError(error)
 }
 
 type FilterController interface {
 	Controller
 	Refilter(filter.Filter) error
+	Filter() filter.Filter
+	CloseWithError(error)
 }
 
 type BaseHandler interface {
-	OnCreate(*networkingv1beta1.Ingress)
-	OnUpdate(*networkingv1beta1.Ingress)
-	OnDelete(*networkingv1beta1.Ingress)
+	OnCreate(*networkingv1.Ingress)
+	OnUpdate(*networkingv1.Ingress)
+	OnDelete(*networkingv1.Ingress)
 }
 
 type Handler interface {
 	BaseHandler
-	OnInitialize([]*networkingv1beta1.Ingress)
+	OnInitialize([]*networkingv1.Ingress)
 }
 
 type HandlerBuilder interface {
-	OnInitialize(func([]*networkingv1beta1.Ingress)) HandlerBuilder
-	OnCreate(func(*networkingv1beta1.Ingress)) HandlerBuilder
-	OnUpdate(func(*networkingv1beta1.Ingress)) HandlerBuilder
-	OnDelete(func(*networkingv1beta1.Ingress)) HandlerBuilder
+	OnInitialize(func([]*networkingv1.Ingress)) HandlerBuilder
+	OnCreate(func(*networkingv1.Ingress)) HandlerBuilder
+	OnUpdate(func(*networkingv1.Ingress)) HandlerBuilder
+	OnDelete(func(*networkingv1.Ingress)) HandlerBuilder
 	Create() Handler
 }
 
 type UnitaryHandler interface {
 	BaseHandler
-	OnInitialize(*networkingv1beta1.Ingress)
+	OnInitialize(*networkingv1.Ingress)
 }
 
 type UnitaryHandlerBuilder interface {
-	OnInitialize(func(*networkingv1beta1.Ingress)) UnitaryHandlerBuilder
-	OnCreate(func(*networkingv1beta1.Ingress)) UnitaryHandlerBuilder
-	OnUpdate(func(*networkingv1beta1.Ingress)) UnitaryHandlerBuilder
-	OnDelete(func(*networkingv1beta1.Ingress)) UnitaryHandlerBuilder
+	OnInitialize(func(*networkingv1.Ingress)) UnitaryHandlerBuilder
+	OnCreate(func(*networkingv1.Ingress)) UnitaryHandlerBuilder
+	OnUpdate(func(*networkingv1.Ingress)) UnitaryHandlerBuilder
+	OnDelete(func(*networkingv1.Ingress)) UnitaryHandlerBuilder
 	Create() UnitaryHandler
 }
 
 type _adapter struct{}
 
-func (_adapter) adaptObject(obj metav1.Object) (*networkingv1beta1.Ingress, error) {
-	if obj, ok := obj.(*networkingv1beta1.Ingress); ok {
+func (_adapter) adaptObject(obj metav1.Object) (*networkingv1.Ingress, error) {
+	if obj, ok := obj.(*networkingv1.Ingress); ok {
 		return obj, nil
 	}
 	return nil, ErrInvalidType
 }
 
-func (a _adapter) adaptList(objs []metav1.Object) ([]*networkingv1beta1.Ingress, error) {
-	var ret []*networkingv1beta1.Ingress
+func (a _adapter) adaptList(objs []metav1.Object) ([]*networkingv1.Ingress, error) {
+	var ret []*networkingv1.Ingress
 	for _, orig := range objs {
 		adapted, err := a.adaptObject(orig)
 		if err != nil {
@@ -135,7 +148,7 @@ type cache struct {
 	parent kcache.CacheReader
 }
 
-func (c *cache) Get(ns string, name string) (*networkingv1beta1.Ingress, error) {
+func (c *cache) Get(ns string, name string) (*networkingv1.Ingress, error) {
 	obj, err := c.parent.Get(ns, name)
 	switch {
 	case err != nil:
@@ -147,7 +160,7 @@ func (c *cache) Get(ns string, name string) (*networkingv1beta1.Ingress, error)
 	}
 }
 
-func (c *cache) List() ([]*networkingv1beta1.Ingress, error) {
+func (c *cache) List() ([]*networkingv1.Ingress, error) {
 	objs, err := c.parent.List()
 	if err != nil {
 		return nil, err
@@ -155,9 +168,47 @@ func (c *cache) List() ([]*networkingv1beta1.Ingress, error) {
 	return adapter.adaptList(objs)
 }
 
+func (c *cache) Snapshot() (Snapshot, error) {
+	parent, err := c.parent.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return snapshot{parent}, nil
+}
+
+type snapshot struct {
+	parent kcache.Snapshot
+}
+
+func (s snapshot) ResourceVersion() string {
+	return s.parent.ResourceVersion()
+}
+
+func (s snapshot) Get(ns string, name string) *networkingv1.Ingress {
+	obj := s.parent.Get(ns, name)
+	if obj == nil {
+		return nil
+	}
+	adapted, _ := adapter.adaptObject(obj)
+	return adapted
+}
+
+func (s snapshot) List() []*networkingv1.Ingress {
+	objs, _ := adapter.adaptList(s.parent.List())
+	return objs
+}
+
+func (s snapshot) ByIndex(index string, value string) ([]*networkingv1.Ingress, error) {
+	objs, err := s.parent.ByIndex(index, value)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.adaptList(objs)
+}
+
 type event struct {
 	etype    kcache.EventType
-	resource *networkingv1beta1.Ingress
+	resource *networkingv1.Ingress
 }
 
 func wrapEvent(evt kcache.Event) (Event, error) {
@@ -172,7 +223,7 @@ func (e event) Type() kcache.EventType {
 	return e.etype
 }
 
-func (e event) Resource() *networkingv1beta1.Ingress {
+func (e event) Resource() *networkingv1.Ingress {
 	return e.resource
 }
 
@@ -268,6 +319,10 @@ func (c *controller) Cache() CacheReader {
 	return c.cache
 }
 
+func (c *controller) Untyped() kcache.Controller {
+	return c.parent
+}
+
 func (c *controller) Subscribe() (Subscription, error) {
 	parent, err := c.parent.Subscribe()
 	if err != nil {
@@ -332,6 +387,14 @@ func (c *filterController) Refilter(f filter.Filter) error {
 	return c.filterParent.Refilter(f)
 }
 
+func (c *filterController) Filter() filter.Filter {
+	return c.filterParent.Filter()
+}
+
+func (c *filterController) CloseWithError(err error) {
+	c.filterParent.CloseWithError(err)
+}
+
 type filterSubscription struct {
 	subscription
 	filterParent kcache.FilterSubscription
@@ -348,6 +411,14 @@ func (s *filterSubscription) Refilter(f filter.Filter) error {
 	return s.filterParent.Refilter(f)
 }
 
+func (s *filterSubscription) Filter() filter.Filter {
+	return s.filterParent.Filter()
+}
+
+func (s *filterSubscription) CloseWithError(err error) {
+	s.filterParent.CloseWithError(err)
+}
+
 func NewMonitor(publisher Publisher, handler Handler) (kcache.Monitor, error) {
 	phandler := kcache.BuildHandler().
 		OnInitialize(func(objs []metav1.Object) {
@@ -379,7 +450,7 @@ func NewMonitor(publisher Publisher, handler Handler) (kcache.Monitor, error) {
 
 func ToUnitary(log logutil.Log, delegate UnitaryHandler) Handler {
 	return BuildHandler().
-		OnInitialize(func(objs []*networkingv1beta1.Ingress) {
+		OnInitialize(func(objs []*networkingv1.Ingress) {
 			if count := len(objs); count > 1 {
 				log.Warnf("initialized with invalid count: %v", count)
 				return
@@ -390,13 +461,13 @@ func ToUnitary(log logutil.Log, delegate UnitaryHandler) Handler {
 			}
 			delegate.OnInitialize(objs[0])
 		}).
-		OnCreate(func(obj *networkingv1beta1.Ingress) {
+		OnCreate(func(obj *networkingv1.Ingress) {
 			delegate.OnCreate(obj)
 		}).
-		OnUpdate(func(obj *networkingv1beta1.Ingress) {
+		OnUpdate(func(obj *networkingv1.Ingress) {
 			delegate.OnUpdate(obj)
 		}).
-		OnDelete(func(obj *networkingv1beta1.Ingress) {
+		OnDelete(func(obj *networkingv1.Ingress) {
 			delegate.OnDelete(obj)
 		}).Create()
 }
@@ -410,39 +481,39 @@ func BuildUnitaryHandler() UnitaryHandlerBuilder {
 }
 
 type baseHandler struct {
-	onCreate func(*networkingv1beta1.Ingress)
-	onUpdate func(*networkingv1beta1.Ingress)
-	onDelete func(*networkingv1beta1.Ingress)
+	onCreate func(*networkingv1.Ingress)
+	onUpdate func(*networkingv1.Ingress)
+	onDelete func(*networkingv1.Ingress)
 }
 
 type handler struct {
 	baseHandler
-	onInitialize func([]*networkingv1beta1.Ingress)
+	onInitialize func([]*networkingv1.Ingress)
 }
 type handlerBuilder handler
 
 type unitaryHandler struct {
 	baseHandler
-	onInitialize func(*networkingv1beta1.Ingress)
+	onInitialize func(*networkingv1.Ingress)
 }
 type unitaryHandlerBuilder unitaryHandler
 
-func (hb *handlerBuilder) OnInitialize(fn func([]*networkingv1beta1.Ingress)) HandlerBuilder {
+func (hb *handlerBuilder) OnInitialize(fn func([]*networkingv1.Ingress)) HandlerBuilder {
 	hb.onInitialize = fn
 	return hb
 }
 
-func (hb *handlerBuilder) OnCreate(fn func(*networkingv1beta1.Ingress)) HandlerBuilder {
+func (hb *handlerBuilder) OnCreate(fn func(*networkingv1.Ingress)) HandlerBuilder {
 	hb.onCreate = fn
 	return hb
 }
 
-func (hb *handlerBuilder) OnUpdate(fn func(*networkingv1beta1.Ingress)) HandlerBuilder {
+func (hb *handlerBuilder) OnUpdate(fn func(*networkingv1.Ingress)) HandlerBuilder {
 	hb.onUpdate = fn
 	return hb
 }
 
-func (hb *handlerBuilder) OnDelete(fn func(*networkingv1beta1.Ingress)) HandlerBuilder {
+func (hb *handlerBuilder) OnDelete(fn func(*networkingv1.Ingress)) HandlerBuilder {
 	hb.onDelete = fn
 	return hb
 }
@@ -451,28 +522,28 @@ func (hb *handlerBuilder) Create() Handler {
 	return handler(*hb)
 }
 
-func (h handler) OnInitialize(objs []*networkingv1beta1.Ingress) {
+func (h handler) OnInitialize(objs []*networkingv1.Ingress) {
 	if h.onInitialize != nil {
 		h.onInitialize(objs)
 	}
 }
 
-func (hb *unitaryHandlerBuilder) OnInitialize(fn func(*networkingv1beta1.Ingress)) UnitaryHandlerBuilder {
+func (hb *unitaryHandlerBuilder) OnInitialize(fn func(*networkingv1.Ingress)) UnitaryHandlerBuilder {
 	hb.onInitialize = fn
 	return hb
 }
 
-func (hb *unitaryHandlerBuilder) OnCreate(fn func(*networkingv1beta1.Ingress)) UnitaryHandlerBuilder {
+func (hb *unitaryHandlerBuilder) OnCreate(fn func(*networkingv1.Ingress)) UnitaryHandlerBuilder {
 	hb.onCreate = fn
 	return hb
 }
 
-func (hb *unitaryHandlerBuilder) OnUpdate(fn func(*networkingv1beta1.Ingress)) UnitaryHandlerBuilder {
+func (hb *unitaryHandlerBuilder) OnUpdate(fn func(*networkingv1.Ingress)) UnitaryHandlerBuilder {
 	hb.onUpdate = fn
 	return hb
 }
 
-func (hb *unitaryHandlerBuilder) OnDelete(fn func(*networkingv1beta1.Ingress)) UnitaryHandlerBuilder {
+func (hb *unitaryHandlerBuilder) OnDelete(fn func(*networkingv1.Ingress)) UnitaryHandlerBuilder {
 	hb.onDelete = fn
 	return hb
 }
@@ -481,25 +552,25 @@ func (hb *unitaryHandlerBuilder) Create() UnitaryHandler {
 	return unitaryHandler(*hb)
 }
 
-func (h unitaryHandler) OnInitialize(obj *networkingv1beta1.Ingress) {
+func (h unitaryHandler) OnInitialize(obj *networkingv1.Ingress) {
 	if h.onInitialize != nil {
 		h.onInitialize(obj)
 	}
 }
 
-func (h baseHandler) OnCreate(obj *networkingv1beta1.Ingress) {
+func (h baseHandler) OnCreate(obj *networkingv1.Ingress) {
 	if h.onCreate != nil {
 		h.onCreate(obj)
 	}
 }
 
-func (h baseHandler) OnUpdate(obj *networkingv1beta1.Ingress) {
+func (h baseHandler) OnUpdate(obj *networkingv1.Ingress) {
 	if h.onUpdate != nil {
 		h.onUpdate(obj)
 	}
 }
 
-func (h baseHandler) OnDelete(obj *networkingv1beta1.Ingress) {
+func (h baseHandler) OnDelete(obj *networkingv1.Ingress) {
 	if h.onDelete != nil {
 		h.onDelete(obj)
 	}
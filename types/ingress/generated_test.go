@@ -9,7 +9,7 @@ import (
 	"testing"
 	"time"
 
-	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/watch"
 
 	logutil "github.com/boz/go-logutil"
@@ -44,7 +44,7 @@ func TestController(t *testing.T) {
 
 	fltr := filter.NSName(nsname.New(obj_a.GetNamespace(), obj_a.GetName()))
 
-	list := &networkingv1beta1.IngressList{
+	list := &networkingv1.IngressList{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "IngressList",
 			APIVersion: "1",
@@ -52,7 +52,7 @@ func TestController(t *testing.T) {
 		ListMeta: metav1.ListMeta{
 			ResourceVersion: "1",
 		},
-		Items: []networkingv1beta1.Ingress{
+		Items: []networkingv1.Ingress{
 			*obj_a,
 			*obj_b,
 		},
@@ -137,6 +137,13 @@ func TestController(t *testing.T) {
 			assert.Equal(t, obj_b.GetName(), obj.GetName(), name)
 		}
 
+		snap, err := c.Cache().Snapshot()
+		if assert.NoError(t, err, name) {
+			assert.Equal(t, "1", snap.ResourceVersion(), name)
+			assert.Len(t, snap.List(), 2, name)
+			assert.NotNil(t, snap.Get(obj_a.GetNamespace(), obj_a.GetName()), name)
+		}
+
 	}
 
 	halfcache := func(name string, c CacheController) {
@@ -310,7 +317,7 @@ func TestMonitor(t *testing.T) {
 	obj_c := testGenObject("ns", "a", "3")
 	obj_d := testGenObject("ns", "b", "4")
 
-	list := &networkingv1beta1.IngressList{
+	list := &networkingv1.IngressList{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "IngressList",
 			APIVersion: "1",
@@ -318,7 +325,7 @@ func TestMonitor(t *testing.T) {
 		ListMeta: metav1.ListMeta{
 			ResourceVersion: "1",
 		},
-		Items: []networkingv1beta1.Ingress{
+		Items: []networkingv1.Ingress{
 			*obj_a,
 		},
 	}
@@ -343,39 +350,39 @@ func TestMonitor(t *testing.T) {
 	u_ucalled := make(chan bool)
 	u_dcalled := make(chan bool)
 
-	h := BuildHandler().OnInitialize(func(objs []*networkingv1beta1.Ingress) {
+	h := BuildHandler().OnInitialize(func(objs []*networkingv1.Ingress) {
 		if assert.Len(t, objs, 1) {
 			assert.Equal(t, obj_a.GetNamespace(), objs[0].GetNamespace())
 			assert.Equal(t, obj_a.GetName(), objs[0].GetName())
 		}
 		close(icalled)
-	}).OnCreate(func(obj *networkingv1beta1.Ingress) {
+	}).OnCreate(func(obj *networkingv1.Ingress) {
 		assert.Equal(t, obj_b.GetNamespace(), obj.GetNamespace())
 		assert.Equal(t, obj_b.GetName(), obj.GetName())
 		close(ccalled)
-	}).OnUpdate(func(obj *networkingv1beta1.Ingress) {
+	}).OnUpdate(func(obj *networkingv1.Ingress) {
 		assert.Equal(t, obj_c.GetNamespace(), obj.GetNamespace())
 		assert.Equal(t, obj_c.GetName(), obj.GetName())
 		close(ucalled)
-	}).OnDelete(func(obj *networkingv1beta1.Ingress) {
+	}).OnDelete(func(obj *networkingv1.Ingress) {
 		assert.Equal(t, obj_d.GetNamespace(), obj.GetNamespace())
 		assert.Equal(t, obj_d.GetName(), obj.GetName())
 		close(dcalled)
 	}).Create()
 
-	uh := BuildUnitaryHandler().OnInitialize(func(obj *networkingv1beta1.Ingress) {
+	uh := BuildUnitaryHandler().OnInitialize(func(obj *networkingv1.Ingress) {
 		assert.Equal(t, obj_a.GetNamespace(), obj.GetNamespace())
 		assert.Equal(t, obj_a.GetName(), obj.GetName())
 		close(u_icalled)
-	}).OnCreate(func(obj *networkingv1beta1.Ingress) {
+	}).OnCreate(func(obj *networkingv1.Ingress) {
 		assert.Equal(t, obj_b.GetNamespace(), obj.GetNamespace())
 		assert.Equal(t, obj_b.GetName(), obj.GetName())
 		close(u_ccalled)
-	}).OnUpdate(func(obj *networkingv1beta1.Ingress) {
+	}).OnUpdate(func(obj *networkingv1.Ingress) {
 		assert.Equal(t, obj_c.GetNamespace(), obj.GetNamespace())
 		assert.Equal(t, obj_c.GetName(), obj.GetName())
 		close(u_ucalled)
-	}).OnDelete(func(obj *networkingv1beta1.Ingress) {
+	}).OnDelete(func(obj *networkingv1.Ingress) {
 		assert.Equal(t, obj_d.GetNamespace(), obj.GetNamespace())
 		assert.Equal(t, obj_d.GetName(), obj.GetName())
 		close(u_dcalled)
@@ -461,8 +468,8 @@ func TestMonitor(t *testing.T) {
 
 }
 
-func testGenObject(ns, name, vsn string) *networkingv1beta1.Ingress {
-	return &networkingv1beta1.Ingress{
+func testGenObject(ns, name, vsn string) *networkingv1.Ingress {
+	return &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:       ns,
 			Name:            name,
@@ -6,6 +6,7 @@ import (
 	"github.com/boz/kcache/filter"
 	"github.com/boz/kcache/nsname"
 	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func PodsFilter(sources ...*batchv1.Job) filter.ComparableFilter {
@@ -40,3 +41,23 @@ func PodsFilter(sources ...*batchv1.Job) filter.ComparableFilter {
 	return filter.Or(filters...)
 
 }
+
+// OwnedPodsFilter() returns a filter which accepts pods owned
+// by the given jobs.
+func OwnedPodsFilter(sources ...*batchv1.Job) filter.ComparableFilter {
+	objs := make([]metav1.Object, 0, len(sources))
+	for _, obj := range sources {
+		objs = append(objs, obj)
+	}
+	return filter.DependentsOf(objs...)
+}
+
+// OwnersFilter() returns a filter which accepts the
+// owners of the given jobs.
+func OwnersFilter(sources ...*batchv1.Job) filter.ComparableFilter {
+	objs := make([]metav1.Object, 0, len(sources))
+	for _, obj := range sources {
+		objs = append(objs, obj)
+	}
+	return filter.OwnersOf(objs...)
+}
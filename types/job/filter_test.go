@@ -5,8 +5,8 @@ import (
 
 	"github.com/boz/kcache/types/job"
 	"github.com/stretchr/testify/assert"
-	v1 "k8s.io/api/core/v1"
 	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -74,3 +74,24 @@ func testPodsFilter(t *testing.T, gen func(string, string, map[string]string) *b
 	assert.True(t, job.PodsFilter(s4, s3).Equals(job.PodsFilter(s3, s4)), ctx)
 
 }
+
+func TestOwnerFilters(t *testing.T) {
+	j := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+		UID:             "job",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", UID: "cron"}},
+	}}
+
+	owned := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		OwnerReferences: []metav1.OwnerReference{{Kind: "Job", UID: "job"}},
+	}}
+	other := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		OwnerReferences: []metav1.OwnerReference{{Kind: "Job", UID: "other"}},
+	}}
+
+	assert.True(t, job.OwnedPodsFilter(j).Accept(owned))
+	assert.False(t, job.OwnedPodsFilter(j).Accept(other))
+
+	cron := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{UID: "cron"}}
+	assert.True(t, job.OwnersFilter(j).Accept(cron))
+	assert.False(t, job.OwnersFilter(j).Accept(&batchv1.CronJob{}))
+}
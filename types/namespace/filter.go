@@ -0,0 +1,17 @@
+package namespace
+
+import (
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ObjectsFilter() returns a filter which accepts objects
+// in the given namespaces.
+func ObjectsFilter(sources ...*corev1.Namespace) filter.ComparableFilter {
+	ids := make([]nsname.NSName, 0, len(sources))
+	for _, ns := range sources {
+		ids = append(ids, nsname.New(ns.Name, ""))
+	}
+	return filter.NSName(ids...)
+}
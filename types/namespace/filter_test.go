@@ -0,0 +1,29 @@
+package namespace_test
+
+import (
+	"testing"
+
+	"github.com/boz/kcache/types/namespace"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestObjectsFilter(t *testing.T) {
+	genns := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	genpod := func(ns string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "pod"}}
+	}
+
+	f := namespace.ObjectsFilter(genns("a"), genns("b"))
+	assert.True(t, f.Accept(genpod("a")))
+	assert.True(t, f.Accept(genpod("b")))
+	assert.False(t, f.Accept(genpod("c")))
+
+	assert.False(t, namespace.ObjectsFilter().Accept(genpod("a")))
+
+	assert.True(t, f.Equals(namespace.ObjectsFilter(genns("b"), genns("a"))))
+	assert.False(t, f.Equals(namespace.ObjectsFilter(genns("a"))))
+}
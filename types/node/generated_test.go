@@ -137,6 +137,13 @@ func TestController(t *testing.T) {
 			assert.Equal(t, obj_b.GetName(), obj.GetName(), name)
 		}
 
+		snap, err := c.Cache().Snapshot()
+		if assert.NoError(t, err, name) {
+			assert.Equal(t, "1", snap.ResourceVersion(), name)
+			assert.Len(t, snap.List(), 2, name)
+			assert.NotNil(t, snap.Get(obj_a.GetNamespace(), obj_a.GetName()), name)
+		}
+
 	}
 
 	halfcache := func(name string, c CacheController) {
@@ -0,0 +1,19 @@
+package persistentvolume
+
+import (
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ClaimsFilter() returns a filter which accepts the persistent
+// volume claims bound to the given volumes.
+func ClaimsFilter(sources ...*corev1.PersistentVolume) filter.ComparableFilter {
+	ids := make([]nsname.NSName, 0, len(sources))
+	for _, pv := range sources {
+		if ref := pv.Spec.ClaimRef; ref != nil && ref.Namespace != "" && ref.Name != "" {
+			ids = append(ids, nsname.New(ref.Namespace, ref.Name))
+		}
+	}
+	return filter.NSName(ids...)
+}
@@ -0,0 +1,33 @@
+package persistentvolume_test
+
+import (
+	"testing"
+
+	"github.com/boz/kcache/types/persistentvolume"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClaimsFilter(t *testing.T) {
+	genpv := func(name string, ref *corev1.ObjectReference) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.PersistentVolumeSpec{ClaimRef: ref},
+		}
+	}
+	genpvc := func(ns, name string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+	}
+
+	pv1 := genpv("1", &corev1.ObjectReference{Namespace: "a", Name: "claim"})
+	pv2 := genpv("2", nil)
+
+	f := persistentvolume.ClaimsFilter(pv1, pv2)
+	assert.True(t, f.Accept(genpvc("a", "claim")))
+	assert.False(t, f.Accept(genpvc("b", "claim")))
+	assert.False(t, f.Accept(genpvc("a", "other")))
+
+	assert.False(t, persistentvolume.ClaimsFilter(pv2).Accept(genpvc("a", "claim")))
+	assert.True(t, f.Equals(persistentvolume.ClaimsFilter(pv1)))
+}
@@ -0,0 +1,126 @@
+package persistentvolumeclaim
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	filter.RegisterFunc("persistentvolumeclaim.pods", func(args []string) (filter.ComparableFilter, error) {
+		ids, err := parseIDs(args)
+		if err != nil {
+			return nil, err
+		}
+		return podsFilter(ids), nil
+	})
+	filter.RegisterKind("persistentvolumeclaim.pods", func(spec json.RawMessage) (filter.ComparableFilter, error) {
+		var args []string
+		if err := json.Unmarshal(spec, &args); err != nil {
+			return nil, err
+		}
+		ids, err := parseIDs(args)
+		if err != nil {
+			return nil, err
+		}
+		return podsFilter(ids), nil
+	})
+}
+
+// PodsFilter() returns a filter which accepts pods that mount
+// one of the given claims.
+func PodsFilter(sources ...*corev1.PersistentVolumeClaim) filter.ComparableFilter {
+	ids := make(map[nsname.NSName]bool, len(sources))
+	for _, pvc := range sources {
+		ids[nsname.ForObject(pvc)] = true
+	}
+	return podsFilter(ids)
+}
+
+// VolumesFilter() returns a filter which accepts the persistent
+// volumes bound to the given claims.
+func VolumesFilter(sources ...*corev1.PersistentVolumeClaim) filter.ComparableFilter {
+	ids := make([]nsname.NSName, 0, len(sources))
+	for _, pvc := range sources {
+		if name := pvc.Spec.VolumeName; name != "" {
+			ids = append(ids, nsname.New("", name))
+		}
+	}
+	return filter.NSName(ids...)
+}
+
+type podsFilter map[nsname.NSName]bool
+
+func (f podsFilter) Accept(obj metav1.Object) bool {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return false
+	}
+	for _, name := range podClaims(pod) {
+		if f[nsname.New(pod.Namespace, name)] {
+			return true
+		}
+	}
+	return false
+}
+
+func (f podsFilter) Equals(other filter.Filter) bool {
+	o, ok := other.(podsFilter)
+	if !ok || len(f) != len(o) {
+		return false
+	}
+	for id := range f {
+		if !o[id] {
+			return false
+		}
+	}
+	return true
+}
+
+func (f podsFilter) String() string {
+	return filter.Call("persistentvolumeclaim.pods", f.ids()...)
+}
+
+func (f podsFilter) MarshalJSON() ([]byte, error) {
+	return filter.MarshalKind("persistentvolumeclaim.pods", f.ids())
+}
+
+func (f podsFilter) ids() []string {
+	ids := make([]string, 0, len(f))
+	for id := range f {
+		ids = append(ids, id.String())
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func parseIDs(args []string) (map[nsname.NSName]bool, error) {
+	ids := make(map[nsname.NSName]bool, len(args))
+	for _, arg := range args {
+		id, err := nsname.Parse(arg)
+		if err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+	return ids, nil
+}
+
+// podClaims() returns the names of the claims mounted by pod.
+func podClaims(pod *corev1.Pod) []string {
+	var names []string
+	for _, v := range pod.Spec.Volumes {
+		switch {
+		case v.PersistentVolumeClaim != nil:
+			names = append(names, v.PersistentVolumeClaim.ClaimName)
+		case v.Ephemeral != nil:
+			// generic ephemeral volumes are backed by a claim named <pod>-<volume>
+			names = append(names, pod.Name+"-"+v.Name)
+		}
+	}
+	return names
+}
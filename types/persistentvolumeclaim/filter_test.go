@@ -0,0 +1,71 @@
+package persistentvolumeclaim_test
+
+import (
+	"testing"
+
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/types/persistentvolumeclaim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func genpvc(ns, name, volume string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: volume},
+	}
+}
+
+func TestPodsFilter(t *testing.T) {
+	genpod := func(ns, name string, volumes ...corev1.Volume) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Spec:       corev1.PodSpec{Volumes: volumes},
+		}
+	}
+
+	claim := corev1.Volume{Name: "data", VolumeSource: corev1.VolumeSource{
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "claim"},
+	}}
+	ephemeral := corev1.Volume{Name: "scratch", VolumeSource: corev1.VolumeSource{
+		Ephemeral: &corev1.EphemeralVolumeSource{},
+	}}
+
+	f := persistentvolumeclaim.PodsFilter(genpvc("a", "claim", ""), genpvc("a", "web-scratch", ""))
+	assert.True(t, f.Accept(genpod("a", "db", claim)))
+	assert.False(t, f.Accept(genpod("b", "db", claim)))
+	assert.True(t, f.Accept(genpod("a", "web", ephemeral)))
+	assert.False(t, f.Accept(genpod("a", "api", ephemeral)))
+	assert.False(t, f.Accept(genpod("a", "db")))
+
+	assert.False(t, persistentvolumeclaim.PodsFilter().Accept(genpod("a", "db", claim)))
+
+	assert.True(t, f.Equals(persistentvolumeclaim.PodsFilter(genpvc("a", "web-scratch", ""), genpvc("a", "claim", ""))))
+	assert.False(t, f.Equals(persistentvolumeclaim.PodsFilter(genpvc("a", "claim", ""))))
+
+	expr := f.(interface{ String() string }).String()
+	parsed, err := filter.Parse(expr)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(parsed), expr)
+
+	buf, err := filter.Marshal(f)
+	require.NoError(t, err)
+	decoded, err := filter.Unmarshal(buf)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(decoded))
+}
+
+func TestVolumesFilter(t *testing.T) {
+	genpv := func(name string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	f := persistentvolumeclaim.VolumesFilter(genpvc("a", "1", "pv-1"), genpvc("a", "2", ""))
+	assert.True(t, f.Accept(genpv("pv-1")))
+	assert.False(t, f.Accept(genpv("pv-2")))
+	assert.False(t, f.Accept(genpv("")))
+
+	assert.False(t, persistentvolumeclaim.VolumesFilter(genpvc("a", "2", "")).Accept(genpv("pv-1")))
+}
@@ -1,14 +1,30 @@
 package pod
 
 import (
+	"encoding/json"
 	"reflect"
+	"sort"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
 	corev1 "k8s.io/api/core/v1"
 )
 
+func init() {
+	filter.RegisterFunc("pod.node", func(args []string) (filter.ComparableFilter, error) {
+		return NodeFilter(args...), nil
+	})
+	filter.RegisterKind("pod.node", func(spec json.RawMessage) (filter.ComparableFilter, error) {
+		var names []string
+		if err := json.Unmarshal(spec, &names); err != nil {
+			return nil, err
+		}
+		return NodeFilter(names...), nil
+	})
+}
+
 func NodeFilter(names ...string) filter.ComparableFilter {
 	set := make(map[string]interface{})
 	for _, name := range names {
@@ -34,3 +50,42 @@ func (f nodeFilter) Equals(other filter.Filter) bool {
 	}
 	return false
 }
+
+func (f nodeFilter) String() string {
+	return filter.Call("pod.node", f.names()...)
+}
+
+func (f nodeFilter) MarshalJSON() ([]byte, error) {
+	return filter.MarshalKind("pod.node", f.names())
+}
+
+func (f nodeFilter) names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OwnersFilter() returns a filter which accepts the
+// owners of the given pods.
+func OwnersFilter(sources ...*corev1.Pod) filter.ComparableFilter {
+	objs := make([]metav1.Object, 0, len(sources))
+	for _, obj := range sources {
+		objs = append(objs, obj)
+	}
+	return filter.OwnersOf(objs...)
+}
+
+// NodesFilter() returns a filter which accepts the nodes
+// that the given pods are scheduled on.
+func NodesFilter(sources ...*corev1.Pod) filter.ComparableFilter {
+	var ids []nsname.NSName
+	for _, pod := range sources {
+		if name := pod.Spec.NodeName; name != "" {
+			ids = append(ids, nsname.New("", name))
+		}
+	}
+	return filter.NSName(ids...)
+}
@@ -1,10 +1,13 @@
 package pod_test
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/boz/kcache/filter"
 	"github.com/boz/kcache/types/pod"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,8 +37,57 @@ func TestNodeFilter(t *testing.T) {
 	assert.False(t, pod.NodeFilter().Equals(other))
 }
 
+func TestNodeFilter_parse(t *testing.T) {
+	f := pod.NodeFilter("b", "a")
+	assert.Equal(t, "pod.node(a, b)", f.(fmt.Stringer).String())
+
+	parsed, err := filter.Parse("pod.node(a, b)")
+	require.NoError(t, err)
+	assert.True(t, f.Equals(parsed))
+}
+
+func TestNodeFilter_json(t *testing.T) {
+	f := pod.NodeFilter("b", "a")
+
+	buf, err := filter.Marshal(f)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"kind":"pod.node","spec":["a","b"]}`, string(buf))
+
+	decoded, err := filter.Unmarshal(buf)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(decoded))
+}
+
 type otherFilter map[string]interface{}
 
 func (otherFilter) Accept(_ metav1.Object) bool {
 	return false
 }
+
+func TestOwnersFilter(t *testing.T) {
+	p := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", UID: "rs"}},
+	}}
+
+	rs := &v1.ReplicationController{ObjectMeta: metav1.ObjectMeta{UID: "rs"}}
+	assert.True(t, pod.OwnersFilter(p).Accept(rs))
+	assert.False(t, pod.OwnersFilter(p).Accept(&v1.ReplicationController{}))
+	assert.False(t, pod.OwnersFilter(&v1.Pod{}).Accept(rs))
+}
+
+func TestNodesFilter(t *testing.T) {
+	genpod := func(node string) *v1.Pod {
+		return &v1.Pod{Spec: v1.PodSpec{NodeName: node}}
+	}
+	gennode := func(name string) *v1.Node {
+		return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	assert.True(t, pod.NodesFilter(genpod("a")).Accept(gennode("a")))
+	assert.True(t, pod.NodesFilter(genpod("a"), genpod("b")).Accept(gennode("b")))
+	assert.False(t, pod.NodesFilter(genpod("a")).Accept(gennode("b")))
+	assert.False(t, pod.NodesFilter(genpod("")).Accept(gennode("a")))
+	assert.False(t, pod.NodesFilter().Accept(gennode("a")))
+
+	assert.True(t, pod.NodesFilter(genpod("a"), genpod("b")).Equals(pod.NodesFilter(genpod("b"), genpod("a"))))
+}
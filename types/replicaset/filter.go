@@ -4,6 +4,7 @@ import (
 	"sort"
 
 	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/boz/kcache/filter"
 	"github.com/boz/kcache/nsname"
@@ -40,3 +41,23 @@ func PodsFilter(sources ...*appsv1.ReplicaSet) filter.ComparableFilter {
 
 	return filter.Or(filters...)
 }
+
+// OwnedPodsFilter() returns a filter which accepts pods owned
+// by the given replica sets.
+func OwnedPodsFilter(sources ...*appsv1.ReplicaSet) filter.ComparableFilter {
+	objs := make([]metav1.Object, 0, len(sources))
+	for _, obj := range sources {
+		objs = append(objs, obj)
+	}
+	return filter.DependentsOf(objs...)
+}
+
+// OwnersFilter() returns a filter which accepts the
+// owners of the given replica sets.
+func OwnersFilter(sources ...*appsv1.ReplicaSet) filter.ComparableFilter {
+	objs := make([]metav1.Object, 0, len(sources))
+	for _, obj := range sources {
+		objs = append(objs, obj)
+	}
+	return filter.OwnersOf(objs...)
+}
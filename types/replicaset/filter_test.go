@@ -74,3 +74,25 @@ func testPodsFilter(t *testing.T, gen func(string, string, map[string]string) *v
 	assert.True(t, replicaset.PodsFilter(s4, s3).Equals(replicaset.PodsFilter(s3, s4)), ctx)
 
 }
+
+func TestOwnerFilters(t *testing.T) {
+	rs := &v1beta1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		UID:             "rs",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", UID: "dep"}},
+	}}
+
+	owned := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", UID: "rs"}},
+	}}
+	other := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", UID: "other"}},
+	}}
+
+	assert.True(t, replicaset.OwnedPodsFilter(rs).Accept(owned))
+	assert.False(t, replicaset.OwnedPodsFilter(rs).Accept(other))
+	assert.False(t, replicaset.OwnedPodsFilter().Accept(owned))
+
+	dep := &v1beta1.Deployment{ObjectMeta: metav1.ObjectMeta{UID: "dep"}}
+	assert.True(t, replicaset.OwnersFilter(rs).Accept(dep))
+	assert.False(t, replicaset.OwnersFilter(rs).Accept(&v1beta1.Deployment{}))
+}
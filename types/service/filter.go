@@ -1,6 +1,7 @@
 package service
 
 import (
+	"encoding/json"
 	"sort"
 
 	"github.com/boz/kcache/filter"
@@ -10,6 +11,23 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 )
 
+func init() {
+	filter.RegisterRawFunc("service.selects", func(raw string) (filter.ComparableFilter, error) {
+		target, err := labels.ConvertSelectorToLabelsMap(raw)
+		if err != nil {
+			return nil, err
+		}
+		return SelectorMatchFilter(target), nil
+	})
+	filter.RegisterKind("service.selects", func(spec json.RawMessage) (filter.ComparableFilter, error) {
+		var target map[string]string
+		if err := json.Unmarshal(spec, &target); err != nil {
+			return nil, err
+		}
+		return SelectorMatchFilter(target), nil
+	})
+}
+
 // SelectorMatchFilter() removes all objects that are not services whose
 // selector matches the given target.
 func SelectorMatchFilter(target map[string]string) filter.ComparableFilter {
@@ -49,6 +67,14 @@ func (f *serviceForFilter) Equals(other filter.Filter) bool {
 	return false
 }
 
+func (f *serviceForFilter) String() string {
+	return "service.selects(" + labels.Set(f.target).String() + ")"
+}
+
+func (f *serviceForFilter) MarshalJSON() ([]byte, error) {
+	return filter.MarshalKind("service.selects", f.target)
+}
+
 func PodsFilter(services ...*corev1.Service) filter.ComparableFilter {
 
 	// make a copy and sort
@@ -1,10 +1,13 @@
 package service_test
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/boz/kcache/filter"
 	"github.com/boz/kcache/types/service"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -59,6 +62,37 @@ func TestSelectorMatchFilter(t *testing.T) {
 	}
 }
 
+func TestSelectorMatchFilter_parse(t *testing.T) {
+	f := service.SelectorMatchFilter(map[string]string{"b": "2", "a": "1"})
+	assert.Equal(t, "service.selects(a=1,b=2)", f.(fmt.Stringer).String())
+
+	parsed, err := filter.Parse("service.selects(a=1,b=2)")
+	require.NoError(t, err)
+	assert.True(t, f.Equals(parsed))
+}
+
+func TestSelectorMatchFilter_json(t *testing.T) {
+	f := service.SelectorMatchFilter(map[string]string{"a": "1"})
+
+	buf, err := filter.Marshal(f)
+	require.NoError(t, err)
+
+	decoded, err := filter.Unmarshal(buf)
+	require.NoError(t, err)
+	assert.True(t, f.Equals(decoded))
+
+	buf, err = filter.Marshal(service.PodsFilter(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "1"},
+		Spec:       v1.ServiceSpec{Selector: map[string]string{"a": "1"}},
+	}))
+	require.NoError(t, err)
+
+	decoded, err = filter.Unmarshal(buf)
+	require.NoError(t, err)
+	assert.True(t, decoded.Accept(&v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "a", Labels: map[string]string{"a": "1"}}}))
+}
+
 func TestPodsFilter(t *testing.T) {
 
 	genpod := func(ns string, labels map[string]string) *v1.Pod {
@@ -102,3 +136,19 @@ func TestPodsFilter(t *testing.T) {
 	assert.True(t, service.PodsFilter(s4, s3).Equals(service.PodsFilter(s3, s4)))
 
 }
+
+func TestPodsFilter_explain(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "1", Labels: map[string]string{"a": "2"}}}
+
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "1"},
+		Spec:       v1.ServiceSpec{Selector: map[string]string{"a": "1"}},
+	}
+
+	e := filter.Explain(service.PodsFilter(s1), pod)
+	assert.False(t, e.Accepted)
+	require.Len(t, e.Children, 1)
+	require.Len(t, e.Children[0].Children, 2)
+	assert.True(t, e.Children[0].Children[0].Accepted)
+	assert.False(t, e.Children[0].Children[1].Accepted)
+}
@@ -0,0 +1,55 @@
+package serviceaccount
+
+import (
+	"sort"
+
+	"github.com/boz/kcache/filter"
+	"github.com/boz/kcache/nsname"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// PodsFilter() returns a filter which accepts the pods
+// running as the given service accounts.
+func PodsFilter(sources ...*corev1.ServiceAccount) filter.ComparableFilter {
+
+	// make a copy and sort
+	srcs := make([]*corev1.ServiceAccount, len(sources))
+	copy(srcs, sources)
+
+	sort.Slice(srcs, func(i, j int) bool {
+		if srcs[i].Namespace != srcs[j].Namespace {
+			return srcs[i].Namespace < srcs[j].Namespace
+		}
+		return srcs[i].Name < srcs[j].Name
+	})
+
+	filters := make([]filter.Filter, 0, len(srcs))
+
+	for _, sa := range srcs {
+		nsfilter := filter.NSName(nsname.New(sa.GetNamespace(), ""))
+		ffilter := filter.Fields(fields.OneTermEqualSelector("spec.serviceAccountName", sa.GetName()))
+		filters = append(filters, filter.And(nsfilter, ffilter))
+	}
+
+	return filter.Or(filters...)
+}
+
+// SecretsFilter() returns a filter which accepts the secrets and image
+// pull secrets referenced by the given service accounts.
+func SecretsFilter(sources ...*corev1.ServiceAccount) filter.ComparableFilter {
+	var ids []nsname.NSName
+	for _, sa := range sources {
+		for _, ref := range sa.Secrets {
+			if ref.Name != "" {
+				ids = append(ids, nsname.New(sa.Namespace, ref.Name))
+			}
+		}
+		for _, ref := range sa.ImagePullSecrets {
+			if ref.Name != "" {
+				ids = append(ids, nsname.New(sa.Namespace, ref.Name))
+			}
+		}
+	}
+	return filter.NSName(ids...)
+}
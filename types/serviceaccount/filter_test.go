@@ -0,0 +1,52 @@
+package serviceaccount_test
+
+import (
+	"testing"
+
+	"github.com/boz/kcache/types/serviceaccount"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func gensa(ns, name string) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+}
+
+func TestPodsFilter(t *testing.T) {
+	genpod := func(ns, sa string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "pod"},
+			Spec:       corev1.PodSpec{ServiceAccountName: sa},
+		}
+	}
+
+	f := serviceaccount.PodsFilter(gensa("a", "1"), gensa("b", "2"))
+	assert.True(t, f.Accept(genpod("a", "1")))
+	assert.True(t, f.Accept(genpod("b", "2")))
+	assert.False(t, f.Accept(genpod("a", "2")))
+	assert.False(t, f.Accept(genpod("c", "1")))
+
+	assert.False(t, serviceaccount.PodsFilter().Accept(genpod("a", "1")))
+
+	assert.True(t, f.Equals(serviceaccount.PodsFilter(gensa("b", "2"), gensa("a", "1"))))
+	assert.False(t, f.Equals(serviceaccount.PodsFilter(gensa("a", "1"))))
+}
+
+func TestSecretsFilter(t *testing.T) {
+	gensecret := func(ns, name string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+	}
+
+	sa := gensa("a", "1")
+	sa.Secrets = []corev1.ObjectReference{{Name: "token"}}
+	sa.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "registry"}}
+
+	f := serviceaccount.SecretsFilter(sa)
+	assert.True(t, f.Accept(gensecret("a", "token")))
+	assert.True(t, f.Accept(gensecret("a", "registry")))
+	assert.False(t, f.Accept(gensecret("b", "token")))
+	assert.False(t, f.Accept(gensecret("a", "other")))
+
+	assert.False(t, serviceaccount.SecretsFilter(gensa("a", "1")).Accept(gensecret("a", "token")))
+}
@@ -2,16 +2,23 @@ package kcache
 
 import (
 	"context"
+	builtin_errors "errors"
+	"net/http"
 
 	lifecycle "github.com/boz/go-lifecycle"
 	logutil "github.com/boz/go-logutil"
 	"github.com/boz/kcache/client"
 	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
 )
 
+var (
+	errWatchExpired = builtin_errors.New("watch version expired")
+)
+
 type watchSession interface {
 	events() <-chan Event
 	done() <-chan struct{}
@@ -27,30 +34,35 @@ func (nullWatchSession) stop()                 {}
 func (nullWatchSession) Error() error          { return nil }
 
 type _watchSession struct {
-	client  client.WatchClient
-	version string
+	client   client.WatchClient
+	version  string
+	resource string
 
 	outch chan Event
 
+	env environment
+
 	ctx    context.Context
 	cancel context.CancelFunc
 	log    logutil.Log
 	lc     lifecycle.Lifecycle
 }
 
-func newWatchSession(ctx context.Context, log logutil.Log, client client.WatchClient, version string) watchSession {
+func newWatchSession(ctx context.Context, log logutil.Log, env environment, client client.WatchClient, target watchTarget) watchSession {
 	lc := lifecycle.New()
 
 	ctx, cancel := context.WithCancel(ctx)
 
 	s := &_watchSession{
-		client:  client,
-		version: version,
-		outch:   make(chan Event, EventBufsiz),
-		ctx:     ctx,
-		cancel:  cancel,
-		log:     log.WithComponent("watch-session"),
-		lc:      lc,
+		client:   client,
+		version:  target.version,
+		resource: target.resource,
+		outch:    make(chan Event, EventBufsiz),
+		env:      env,
+		ctx:      ctx,
+		cancel:   cancel,
+		log:      log.WithComponent("watch-session"),
+		lc:       lc,
 	}
 
 	go lc.WatchContext(ctx)
@@ -78,10 +90,28 @@ func (s *_watchSession) run() {
 	defer s.lc.ShutdownCompleted()
 	defer s.cancel()
 
-	conn, err := s.connect()
+	ctx, span := s.env.tracer.Start(s.ctx, "kcache.watch.session",
+		StringAttribute(AttributeResource, s.resource),
+		StringAttribute(AttributeNamespace, clientNamespace(s.client)),
+		StringAttribute(AttributeResourceVersion, s.version))
+
+	var reason error
+	count := 0
+
+	defer func() {
+		span.SetAttributes(IntAttribute(AttributeEvents, count))
+		span.End(reason)
+	}()
+
+	shutdown := func(err error) {
+		reason = err
+		s.lc.ShutdownInitiated(err)
+	}
+
+	conn, err := s.connect(ctx)
 	if err != nil {
 		s.log.Debugf("connecting to server: %v", err)
-		s.lc.ShutdownInitiated(errors.Wrap(err, "connecting to server"))
+		shutdown(errors.Wrap(err, "connecting to server"))
 		return
 	}
 
@@ -92,61 +122,87 @@ func (s *_watchSession) run() {
 
 		case err := <-s.lc.ShutdownRequest():
 
-			s.lc.ShutdownInitiated(err)
+			shutdown(err)
 			return
 
 		case kevt, ok := <-conn.ResultChan():
 
 			if !ok {
-				s.lc.ShutdownInitiated(nil)
+				shutdown(nil)
 				return
 			}
 
 			if status, ok := kevt.Object.(*metav1.Status); ok {
 				s.logStatus(status)
+				if status.Code == http.StatusGone {
+					shutdown(errors.WithStack(errWatchExpired))
+					return
+				}
 				continue
 			}
 
 			obj, err := meta.Accessor(kevt.Object)
 			if err != nil {
-				s.lc.ShutdownInitiated(errors.Wrap(err, "meta accessor"))
+				shutdown(errors.Wrap(err, "meta accessor"))
 				return
 			}
 
-			var evt Event
+			var et EventType
 
 			switch kevt.Type {
 			case watch.Added:
-				evt = NewEvent(EventTypeCreate, obj)
+				et = EventTypeCreate
 			case watch.Modified:
-				evt = NewEvent(EventTypeUpdate, obj)
+				et = EventTypeUpdate
 			case watch.Deleted:
-				evt = NewEvent(EventTypeDelete, obj)
+				et = EventTypeDelete
 			}
 
-			if evt == nil {
+			if et == "" {
 				s.log.Debugf("unknown event type: %v", kevt.Type)
 				continue
 			}
 
+			// events outlive the session; their context only carries its span.
+			ectx, espan := s.env.tracer.Start(detached{ctx}, "kcache.watch.event")
+			evt := newEvent(ectx, et, obj)
+			espan.SetAttributes(eventAttributes(evt)...)
+			count++
+
 			select {
 			case s.outch <- evt:
+				s.env.metrics.BufferFill(len(s.outch), cap(s.outch))
+				espan.End(nil)
 			default:
+				s.env.metrics.EventDropped()
 				s.log.Warnf("output buffer full; event missed.")
+				espan.End(errEventDropped)
 			}
 
 		}
 	}
 }
 
-func (s *_watchSession) connect() (watch.Interface, error) {
-	response, err := s.client.Watch(s.ctx, metav1.ListOptions{
+func (s *_watchSession) connect(ctx context.Context) (watch.Interface, error) {
+	ctx, span := s.env.tracer.Start(ctx, "kcache.watch.connect",
+		StringAttribute(AttributeResource, s.resource),
+		StringAttribute(AttributeNamespace, clientNamespace(s.client)),
+		StringAttribute(AttributeResourceVersion, s.version))
+	response, err := s.client.Watch(ctx, metav1.ListOptions{
 		ResourceVersion: s.version,
 		Watch:           true,
 	})
+	span.End(err)
 	return response, err
 }
 
+// isExpired() returns true if err shows that the version
+// being watched is too old to resume from.
+func isExpired(err error) bool {
+	err = errors.Cause(err)
+	return err == errWatchExpired || apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
+
 func (s *_watchSession) logStatus(status *metav1.Status) {
 	s.log.Debugf("STATUS: %v %v %v [code: %v vsn: %v]", status.Status, status.Message, status.Reason, status.Code, status.GetResourceVersion())
 }
@@ -7,6 +7,7 @@ import (
 	lifecycle "github.com/boz/go-lifecycle"
 	logutil "github.com/boz/go-logutil"
 	"github.com/boz/kcache/client"
+	"github.com/boz/kcache/clock"
 	"github.com/pkg/errors"
 )
 
@@ -15,9 +16,14 @@ const (
 )
 
 type watcher interface {
-	reset(string) error
+	// reset() restarts the watch at version.  resource is the kind
+	// being watched, for tracing.
+	reset(version string, resource string) error
 	events() <-chan Event
 
+	// expired() is sent to when the watched version is too old to resume from.
+	expired() <-chan struct{}
+
 	Done() <-chan struct{}
 	Error() error
 }
@@ -27,25 +33,30 @@ type _watcher struct {
 
 	client client.WatchClient
 
-	resetch chan string
-	evtch   chan chan (<-chan Event)
+	resetch   chan watchTarget
+	evtch     chan chan (<-chan Event)
+	expiredch chan struct{}
+
+	env environment
 
 	log logutil.Log
 	lc  lifecycle.Lifecycle
 	ctx context.Context
 }
 
-func newWatcher(ctx context.Context, log logutil.Log, stopch <-chan struct{}, client client.WatchClient) watcher {
+func newWatcher(ctx context.Context, log logutil.Log, env environment, stopch <-chan struct{}, client client.WatchClient) watcher {
 	log = log.WithComponent("watcher")
 	lc := lifecycle.New()
 
 	w := &_watcher{
-		client:  client,
-		resetch: make(chan string),
-		evtch:   make(chan chan (<-chan Event)),
-		log:     log,
-		lc:      lc,
-		ctx:     ctx,
+		client:    client,
+		resetch:   make(chan watchTarget),
+		evtch:     make(chan chan (<-chan Event)),
+		expiredch: make(chan struct{}, 1),
+		env:       env,
+		log:       log,
+		lc:        lc,
+		ctx:       ctx,
 	}
 
 	go w.lc.WatchContext(ctx)
@@ -55,9 +66,15 @@ func newWatcher(ctx context.Context, log logutil.Log, stopch <-chan struct{}, cl
 	return w
 }
 
-func (w *_watcher) reset(vsn string) error {
+// watchTarget is the position and kind of a watch.
+type watchTarget struct {
+	version  string
+	resource string
+}
+
+func (w *_watcher) reset(version string, resource string) error {
 	select {
-	case w.resetch <- vsn:
+	case w.resetch <- watchTarget{version, resource}:
 		return nil
 	case <-w.lc.ShuttingDown():
 		return errors.WithStack(ErrNotRunning)
@@ -74,6 +91,10 @@ func (w *_watcher) events() <-chan Event {
 	}
 }
 
+func (w *_watcher) expired() <-chan struct{} {
+	return w.expiredch
+}
+
 func (w *_watcher) Done() <-chan struct{} {
 	return w.lc.Done()
 }
@@ -91,9 +112,13 @@ func (w *_watcher) run() {
 	var session watchSession = nullWatchSession{}
 	var outch chan Event
 
-	var curVersion string
+	var cur watchTarget
 
-	var retry *time.Timer
+	var retry clock.Timer
+
+	// set when the current session was started by a retry.
+	var retrying bool
+	var started time.Time
 
 mainloop:
 	for {
@@ -104,38 +129,58 @@ mainloop:
 			w.lc.ShutdownInitiated(err)
 			break mainloop
 
-		case vsn := <-w.resetch:
-			w.log.Debugf("ressetting to version %v", vsn)
+		case target := <-w.resetch:
+			w.log.Debugf("ressetting to version %v", target.version)
 
 			if retry != nil {
 				retry.Stop()
 				retry = nil
 			}
 
-			session.stop()
-			session = newWatchSession(ctx, w.log, w.client, vsn)
+			if retrying {
+				w.env.metrics.WatchReconnected()
+				retrying = false
+			}
+
+			w.stopSession(session, started, nil)
+			session = newWatchSession(ctx, w.log, w.env, w.client, target)
+			started = w.env.clock.Now()
 			outch = make(chan Event, EventBufsiz)
-			curVersion = vsn
+			cur = target
 
 		case <-session.done():
-			w.log.Debugf("session done.  retrying version %v in %v", curVersion, watchRetryDelay)
+			err := session.Error()
 
-			session.stop()
+			w.stopSession(session, started, err)
 			session = nullWatchSession{}
 			outch = nil
-			retry = w.scheduleRetry(w.resetch, curVersion)
+
+			if isExpired(err) {
+				w.log.Debugf("session done.  version %v expired", cur.version)
+				select {
+				case w.expiredch <- struct{}{}:
+				default:
+				}
+				continue
+			}
+
+			w.log.Debugf("session done.  retrying version %v in %v", cur.version, watchRetryDelay)
+			retry = w.scheduleRetry(w.resetch, cur)
+			retrying = true
 
 		case evt := <-session.events():
 
 			select {
 			case outch <- evt:
+				w.env.metrics.BufferFill(len(outch), cap(outch))
 			default:
+				w.env.metrics.EventDropped()
 				w.log.Errorf("output buffer full")
 			}
 
-			curVersion = evt.Resource().GetResourceVersion()
+			cur.version = evt.Resource().GetResourceVersion()
 
-			w.log.Debugf("session event: %v version: %v", evt, curVersion)
+			w.log.Debugf("session event: %v version: %v", evt, cur.version)
 
 		case reqch := <-w.evtch:
 			reqch <- outch
@@ -149,13 +194,23 @@ mainloop:
 
 	if donech := session.done(); donech != nil {
 		<-donech
+		w.env.metrics.WatchSessionEnded(w.env.clock.Since(started), session.Error())
+	}
+}
+
+// stopSession() stops session and records its length.
+func (w *_watcher) stopSession(session watchSession, started time.Time, err error) {
+	session.stop()
+	if _, ok := session.(nullWatchSession); ok {
+		return
 	}
+	w.env.metrics.WatchSessionEnded(w.env.clock.Since(started), err)
 }
 
-func (w *_watcher) scheduleRetry(ch chan string, vsn string) *time.Timer {
-	return time.AfterFunc(watchRetryDelay, func() {
+func (w *_watcher) scheduleRetry(ch chan watchTarget, target watchTarget) clock.Timer {
+	return w.env.clock.AfterFunc(watchRetryDelay, func() {
 		select {
-		case ch <- vsn:
+		case ch <- target:
 		case <-w.lc.ShuttingDown():
 		}
 	})